@@ -0,0 +1,188 @@
+// Package census reads the Census Bureau's ZCTA-to-county relationship file
+// and ZCTA Gazetteer file.
+//
+// ZIP Code Tabulation Areas (ZCTAs) are Census approximations of USPS ZIP
+// code service areas. Most ZCTAs share a code with the ZIP they approximate,
+// but ZIPs without a meaningful area, such as PO box and unique ZIPs, have no
+// ZCTA.
+package census
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ZCTACounty is the part of a ZCTA that lies in a county. Areas are in
+// square meters.
+type ZCTACounty struct {
+	ZCTA          string
+	CountyFIPS    string
+	CountyName    string
+	LandAreaPart  int64
+	WaterAreaPart int64
+}
+
+// ZCTAGazetteer is a ZCTA's area and internal point. Areas are in square
+// meters unless noted.
+type ZCTAGazetteer struct {
+	ZCTA          string
+	LandArea      int64
+	WaterArea     int64
+	LandAreaSqMi  float64
+	WaterAreaSqMi float64
+	Latitude      float64
+	Longitude     float64
+}
+
+// ReadZCTACountyFile reads a ZCTA-to-county relationship file. Both the
+// pipe-delimited 2020 layout and the comma-delimited 2010 layout are
+// supported. County parts that lie in no ZCTA are skipped.
+func ReadZCTACountyFile(r io.Reader, yield func(ZCTACounty)) error {
+	return readDelimited(r, func(row *row) error {
+		var zc ZCTACounty
+		var err error
+
+		zc.ZCTA = row.field("GEOID_ZCTA5_20", "ZCTA5")
+		if zc.ZCTA == "" {
+			return nil
+		}
+		zc.CountyFIPS = row.field("GEOID_COUNTY_20", "GEOID")
+		zc.CountyName = row.field("NAMELSAD_COUNTY_20")
+
+		if zc.LandAreaPart, err = row.int("AREALAND_PART", "AREALANDPT"); err != nil {
+			return err
+		}
+		if zc.WaterAreaPart, err = row.int("AREAWATER_PART"); err != nil {
+			return err
+		}
+
+		yield(zc)
+		return nil
+	})
+}
+
+// ReadZCTAGazetteerFile reads a tab-delimited ZCTA Gazetteer file.
+func ReadZCTAGazetteerFile(r io.Reader, yield func(ZCTAGazetteer)) error {
+	return readDelimited(r, func(row *row) error {
+		var g ZCTAGazetteer
+		var err error
+
+		g.ZCTA = row.field("GEOID")
+		if g.LandArea, err = row.int("ALAND"); err != nil {
+			return err
+		}
+		if g.WaterArea, err = row.int("AWATER"); err != nil {
+			return err
+		}
+		if g.LandAreaSqMi, err = row.float("ALAND_SQMI"); err != nil {
+			return err
+		}
+		if g.WaterAreaSqMi, err = row.float("AWATER_SQMI"); err != nil {
+			return err
+		}
+		if g.Latitude, err = row.float("INTPTLAT"); err != nil {
+			return err
+		}
+		if g.Longitude, err = row.float("INTPTLONG"); err != nil {
+			return err
+		}
+
+		yield(g)
+		return nil
+	})
+}
+
+type row struct {
+	columns map[string]int
+	values  []string
+}
+
+// readDelimited reads a delimited file with a header row. The delimiter is
+// detected from the header.
+func readDelimited(r io.Reader, parse func(*row) error) error {
+	br := bufio.NewReader(r)
+	first, err := br.Peek(1024)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if i := strings.IndexByte(string(first), '\n'); i >= 0 {
+		first = first[:i]
+	}
+
+	cr := csv.NewReader(br)
+	cr.LazyQuotes = true
+	cr.FieldsPerRecord = -1
+	switch {
+	case strings.Contains(string(first), "|"):
+		cr.Comma = '|'
+	case strings.Contains(string(first), "\t"):
+		cr.Comma = '\t'
+	}
+
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToUpper(strings.TrimSpace(name))] = i
+	}
+
+	for {
+		values, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := parse(&row{columns: columns, values: values}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// field returns the value of the first of names present in the header.
+func (r *row) field(names ...string) string {
+	for _, name := range names {
+		if i, ok := r.columns[name]; ok {
+			if i >= len(r.values) {
+				return ""
+			}
+			return strings.TrimSpace(r.values[i])
+		}
+	}
+	return ""
+}
+
+func (r *row) int(names ...string) (int64, error) {
+	s := r.field(names...)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %v: %q", names[0], s)
+	}
+	return n, nil
+}
+
+func (r *row) float(names ...string) (float64, error) {
+	s := r.field(names...)
+	if s == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %v: %q", names[0], s)
+	}
+	return f, nil
+}
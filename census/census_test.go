@@ -0,0 +1,76 @@
+package census
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readZCTACounties(t *testing.T, s string) []ZCTACounty {
+	var zcs []ZCTACounty
+	if err := ReadZCTACountyFile(strings.NewReader(s), func(zc ZCTACounty) { zcs = append(zcs, zc) }); err != nil {
+		t.Fatal(err)
+	}
+	return zcs
+}
+
+func TestReadZCTACountyFile2020(t *testing.T) {
+	// Pipe-delimited, with a BOM, and a county part that lies in no ZCTA.
+	s := "\ufeffOID_ZCTA5_20|GEOID_ZCTA5_20|NAMELSAD_ZCTA5_20|AREALAND_ZCTA5_20|AREAWATER_ZCTA5_20|MTFCC_ZCTA5_20|CLASSFP_ZCTA5_20|FUNCSTAT_ZCTA5_20|OID_COUNTY_20|GEOID_COUNTY_20|NAMELSAD_COUNTY_20|AREALAND_COUNTY_20|AREAWATER_COUNTY_20|MTFCC_COUNTY_20|CLASSFP_COUNTY_20|FUNCSTAT_COUNTY_20|AREALAND_PART|AREAWATER_PART\n" +
+		"22201|22201|ZCTA5 22201|5493513|0|G6350|B5|S|27590275928513|51013|Arlington County|67329839|1362838|G4020|H6|A|5493513|0\n" +
+		"|||||||||02013|Aleutians East Borough|18051706270|20007879795|G4020|H1|A|3466215|119745\n" +
+		"01001|01001|ZCTA5 01001|29635470|2240373|G6350|B5|S|27590275928514|25013|Hampden County|1598148659|41458425|G4020|H4|N|29635470|2240373\n"
+
+	want := []ZCTACounty{
+		{ZCTA: "22201", CountyFIPS: "51013", CountyName: "Arlington County", LandAreaPart: 5493513},
+		{ZCTA: "01001", CountyFIPS: "25013", CountyName: "Hampden County", LandAreaPart: 29635470, WaterAreaPart: 2240373},
+	}
+	if got := readZCTACounties(t, s); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestReadZCTACountyFile2010(t *testing.T) {
+	// Comma-delimited, with no county name or water area.
+	s := "ZCTA5,STATE,COUNTY,GEOID,POPPT,HUPT,AREAPT,AREALANDPT,ZPOP,ZHU,ZAREA,ZAREALAND\r\n" +
+		"00601,72,001,72001,18465,7695,165132671,164333375,18570,7744,167459085,166659749\r\n" +
+		"00601,72,141,72141,105,49,2326414,2326414,18570,7744,167459085,166659749\r\n"
+
+	want := []ZCTACounty{
+		{ZCTA: "00601", CountyFIPS: "72001", LandAreaPart: 164333375},
+		{ZCTA: "00601", CountyFIPS: "72141", LandAreaPart: 2326414},
+	}
+	if got := readZCTACounties(t, s); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestReadZCTACountyFileInvalid(t *testing.T) {
+	s := "GEOID_ZCTA5_20|GEOID_COUNTY_20|AREALAND_PART\n22201|51013|lots\n"
+	if err := ReadZCTACountyFile(strings.NewReader(s), func(ZCTACounty) {}); err == nil {
+		t.Error("expected error for non-numeric area")
+	}
+	if err := ReadZCTACountyFile(strings.NewReader(""), func(ZCTACounty) {}); err == nil {
+		t.Error("expected error for missing header")
+	}
+}
+
+func TestReadZCTAGazetteerFile(t *testing.T) {
+	// Tab-delimited; the last header has trailing spaces in the real file.
+	s := "GEOID\tALAND\tAWATER\tALAND_SQMI\tAWATER_SQMI\tINTPTLAT\tINTPTLONG                                                                                                               \n" +
+		"00601\t166847909\t799292\t64.42\t0.309\t18.180555\t-66.749961                                 \n" +
+		"22201\t5493513\t0\t2.121\t0\t38.887413\t-77.093544\n"
+
+	var got []ZCTAGazetteer
+	if err := ReadZCTAGazetteerFile(strings.NewReader(s), func(g ZCTAGazetteer) { got = append(got, g) }); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ZCTAGazetteer{
+		{ZCTA: "00601", LandArea: 166847909, WaterArea: 799292, LandAreaSqMi: 64.42, WaterAreaSqMi: 0.309, Latitude: 18.180555, Longitude: -66.749961},
+		{ZCTA: "22201", LandArea: 5493513, LandAreaSqMi: 2.121, Latitude: 38.887413, Longitude: -77.093544},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
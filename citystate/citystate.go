@@ -17,25 +17,28 @@ const (
 )
 
 type CityStateDetail struct {
-	CopyrightDetailCode            string
-	ZipCode                        string
-	CityStateKey                   string
-	ZipClassificationCode          string
-	CityStateName                  string
-	CityStateNameAbbreviation      string
-	CityStateNameFacilityCode      string
-	CityStateMailingNameIndicator  string
-	PreferredLastLineCityStateKey  string
-	PreferredLastLineCityStateName string
-	CityDeliveryIndicator          string
-	CarrierRouteRateSortation      string
-	UniqueZipNameIndicator         string
-	FinanceNumber                  string
-	StateAbbreviation              string
-	CountyNumber                   string
-	CountyName                     string
+	CopyrightDetailCode            string `json:"copyright_detail_code"`
+	ZipCode                        string `json:"zip_code"`
+	CityStateKey                   string `json:"city_state_key"`
+	ZipClassificationCode          string `json:"zip_classification_code"`
+	CityStateName                  string `json:"city_state_name"`
+	CityStateNameAbbreviation      string `json:"city_state_name_abbreviation"`
+	CityStateNameFacilityCode      string `json:"city_state_name_facility_code"`
+	CityStateMailingNameIndicator  string `json:"city_state_mailing_name_indicator"`
+	PreferredLastLineCityStateKey  string `json:"preferred_last_line_city_state_key"`
+	PreferredLastLineCityStateName string `json:"preferred_last_line_city_state_name"`
+	CityDeliveryIndicator          string `json:"city_delivery_indicator"`
+	CarrierRouteRateSortation      string `json:"carrier_route_rate_sortation"`
+	UniqueZipNameIndicator         string `json:"unique_zip_name_indicator"`
+	FinanceNumber                  string `json:"finance_number"`
+	StateAbbreviation              string `json:"state_abbreviation"`
+	CountyNumber                   string `json:"county_number"`
+	CountyName                     string `json:"county_name"`
 }
 
+// ReadCityStateFile reads detail records from a City State file. Change
+// files share the layout but have no action code, so each ZIP they contain
+// replaces that ZIP's records.
 func ReadCityStateFile(r io.Reader, yield func(CityStateDetail)) error {
 	buf := make([]byte, cityStateRecordLength)
 
@@ -0,0 +1,189 @@
+package citystate
+
+import "sort"
+
+// minSimilarity is the least similarity a CityIndex search returns. Different
+// names sharing a prefix such as "SAINT " can score over 0.5.
+const minSimilarity = 0.7
+
+// CityMatch is a city name found by a CityIndex search.
+type CityMatch struct {
+	// Name is the City State name.
+	Name              string   `json:"name"`
+	StateAbbreviation string   `json:"state_abbreviation"`
+	Zips              []string `json:"zips"`
+	// Distance is the edit distance between the normalized query and name.
+	Distance int `json:"distance"`
+	// Similarity is 1 for an exact match of the normalized names, falling
+	// toward 0 as the distance grows relative to their lengths.
+	Similarity float64 `json:"similarity"`
+}
+
+type indexedCity struct {
+	name       string
+	normalized string
+	zips       []string
+}
+
+// CityIndex finds city names within a state that are close to a typed name.
+// Names are compared after NormalizeCityName: candidates sharing trigrams
+// with the query are ranked by edit distance.
+type CityIndex struct {
+	// states holds each state's cities, by normalized name.
+	states map[string]map[string]*indexedCity
+	// trigrams maps each state's trigrams to the normalized names containing
+	// them.
+	trigrams map[string]map[string][]string
+}
+
+// NewCityIndex returns an empty index.
+func NewCityIndex() *CityIndex {
+	return &CityIndex{
+		states:   make(map[string]map[string]*indexedCity),
+		trigrams: make(map[string]map[string][]string),
+	}
+}
+
+// Add indexes a record's city name and its abbreviation, if that normalizes
+// differently. It has the signature expected by ReadCityStateFile.
+func (x *CityIndex) Add(d CityStateDetail) {
+	x.add(d.StateAbbreviation, d.CityStateName, NormalizeCityName(d.CityStateName), d.ZipCode)
+	if abbr := NormalizeCityName(d.CityStateNameAbbreviation); abbr != "" {
+		x.add(d.StateAbbreviation, d.CityStateName, abbr, d.ZipCode)
+	}
+}
+
+func (x *CityIndex) add(state, name, normalized, zip string) {
+	cities, ok := x.states[state]
+	if !ok {
+		cities = make(map[string]*indexedCity)
+		x.states[state] = cities
+		x.trigrams[state] = make(map[string][]string)
+	}
+
+	c, ok := cities[normalized]
+	if !ok {
+		c = &indexedCity{name: name, normalized: normalized}
+		cities[normalized] = c
+		for _, t := range trigrams(normalized) {
+			x.trigrams[state][t] = append(x.trigrams[state][t], normalized)
+		}
+	}
+	if i := sort.SearchStrings(c.zips, zip); i == len(c.zips) || c.zips[i] != zip {
+		c.zips = append(c.zips, "")
+		copy(c.zips[i+1:], c.zips[i:])
+		c.zips[i] = zip
+	}
+}
+
+// Zips returns the sorted ZIPs for which a city is a name or abbreviation in
+// a state, after NormalizeCityName.
+func (x *CityIndex) Zips(state, name string) []string {
+	c, ok := x.states[state][NormalizeCityName(name)]
+	if !ok {
+		return nil
+	}
+	return append([]string(nil), c.zips...)
+}
+
+// Search returns up to limit cities in a state whose names are similar to
+// name, most similar first. A limit of 0 returns all of them.
+func (x *CityIndex) Search(state, name string, limit int) []CityMatch {
+	query := NormalizeCityName(name)
+	if query == "" {
+		return nil
+	}
+	cities := x.states[state]
+
+	seen := make(map[string]bool)
+	var matches []CityMatch
+	for _, t := range trigrams(query) {
+		for _, normalized := range x.trigrams[state][t] {
+			if seen[normalized] {
+				continue
+			}
+			seen[normalized] = true
+
+			d := editDistance(query, normalized)
+			s := similarity(d, query, normalized)
+			if s < minSimilarity {
+				continue
+			}
+			c := cities[normalized]
+			matches = append(matches, CityMatch{
+				Name:              c.name,
+				StateAbbreviation: state,
+				Zips:              append([]string(nil), c.zips...),
+				Distance:          d,
+				Similarity:        s,
+			})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Similarity != matches[j].Similarity {
+			return matches[i].Similarity > matches[j].Similarity
+		}
+		return matches[i].Name < matches[j].Name
+	})
+
+	// A name and its abbreviation can both match; keep the better one.
+	names := make(map[string]bool)
+	out := matches[:0]
+	for _, m := range matches {
+		if !names[m.Name] {
+			names[m.Name] = true
+			out = append(out, m)
+		}
+	}
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// trigrams returns the distinct three-letter substrings of a name padded
+// with spaces, so short names and word starts get trigrams too.
+func trigrams(s string) []string {
+	r := []rune("  " + s + " ")
+	seen := make(map[string]bool)
+	var ts []string
+	for i := 0; i+3 <= len(r); i++ {
+		t := string(r[i : i+3])
+		if !seen[t] {
+			seen[t] = true
+			ts = append(ts, t)
+		}
+	}
+	return ts
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func similarity(distance int, a, b string) float64 {
+	n := max(len([]rune(a)), len([]rune(b)))
+	if n == 0 {
+		return 1
+	}
+	return 1 - float64(distance)/float64(n)
+}
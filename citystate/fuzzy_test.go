@@ -0,0 +1,85 @@
+package citystate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testIndex() *CityIndex {
+	x := NewCityIndex()
+	for _, d := range []CityStateDetail{
+		{ZipCode: "63102", CityStateName: "SAINT LOUIS", CityStateNameAbbreviation: "ST LOUIS", StateAbbreviation: "MO"},
+		{ZipCode: "63101", CityStateName: "SAINT LOUIS", CityStateNameAbbreviation: "ST LOUIS", StateAbbreviation: "MO"},
+		{ZipCode: "63101", CityStateName: "SAINT LOUIS", CityStateNameAbbreviation: "ST LOUIS", StateAbbreviation: "MO"},
+		{ZipCode: "63301", CityStateName: "SAINT CHARLES", CityStateNameAbbreviation: "ST CHARLES", StateAbbreviation: "MO"},
+		{ZipCode: "63366", CityStateName: "O FALLON", StateAbbreviation: "MO"},
+		{ZipCode: "62269", CityStateName: "O FALLON", StateAbbreviation: "IL"},
+		{ZipCode: "76101", CityStateName: "FORT WORTH", CityStateNameAbbreviation: "FT WORTH", StateAbbreviation: "TX"},
+	} {
+		x.Add(d)
+	}
+	return x
+}
+
+func TestCityIndexZips(t *testing.T) {
+	x := testIndex()
+
+	if got, want := x.Zips("MO", "St. Louis"), []string{"63101", "63102"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := x.Zips("IL", "Saint Louis"); got != nil {
+		t.Errorf("other state: got %v", got)
+	}
+}
+
+func TestCityIndexSearch(t *testing.T) {
+	x := testIndex()
+
+	tests := []struct {
+		state, name string
+		limit       int
+		want        []string
+	}{
+		{"MO", "Saint Louis", 0, []string{"SAINT LOUIS"}},
+		{"MO", "St Luois", 0, []string{"SAINT LOUIS"}},
+		{"MO", "Saint Lewis", 0, []string{"SAINT LOUIS"}},
+		{"MO", "St Charls", 0, []string{"SAINT CHARLES"}},
+		{"MO", "OFallon", 0, []string{"O FALLON"}},
+		{"TX", "Ft Wroth", 0, []string{"FORT WORTH"}},
+		{"MO", "Saint", 0, nil},
+		{"MO", "Kansas City", 0, nil},
+		{"IL", "St Louis", 0, nil},
+		{"MO", "Saint Lou", 1, []string{"SAINT LOUIS"}},
+	}
+	for _, test := range tests {
+		var got []string
+		for _, m := range x.Search(test.state, test.name, test.limit) {
+			got = append(got, m.Name)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%v %q: got %v, want %v", test.state, test.name, got, test.want)
+		}
+	}
+
+	m := x.Search("MO", "St Louis", 0)
+	if len(m) != 1 || m[0].Distance != 0 || m[0].Similarity != 1 || !reflect.DeepEqual(m[0].Zips, []string{"63101", "63102"}) {
+		t.Errorf("exact match: got %+v", m)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"ABC", "", 3},
+		{"KITTEN", "SITTING", 3},
+		{"SAINT LOUIS", "SAINT LUOIS", 2},
+	}
+	for _, test := range tests {
+		if got := editDistance(test.a, test.b); got != test.want {
+			t.Errorf("%q, %q: got %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
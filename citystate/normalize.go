@@ -0,0 +1,101 @@
+package citystate
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// cityWords expands abbreviations anywhere in a city name.
+var cityWords = map[string]string{
+	"ST":     "SAINT",
+	"STE":    "SAINTE",
+	"FT":     "FORT",
+	"MT":     "MOUNT",
+	"MTN":    "MOUNTAIN",
+	"PT":     "POINT",
+	"PRT":    "PORT",
+	"HTS":    "HEIGHTS",
+	"SPGS":   "SPRINGS",
+	"SPG":    "SPRING",
+	"JCT":    "JUNCTION",
+	"LK":     "LAKE",
+	"LKS":    "LAKES",
+	"CTR":    "CENTER",
+	"VLG":    "VILLAGE",
+	"BCH":    "BEACH",
+	"CY":     "CITY",
+	"TWP":    "TOWNSHIP",
+	"IS":     "ISLAND",
+	"VLY":    "VALLEY",
+	"FLS":    "FALLS",
+	"HBR":    "HARBOR",
+	"CRK":    "CREEK",
+	"SPRNGS": "SPRINGS",
+}
+
+// cityDirections expands the abbreviated direction that starts a city name,
+// as in "N LAS VEGAS". Elsewhere a single letter is left alone.
+var cityDirections = map[string]string{
+	"N":  "NORTH",
+	"S":  "SOUTH",
+	"E":  "EAST",
+	"W":  "WEST",
+	"NE": "NORTHEAST",
+	"NW": "NORTHWEST",
+	"SE": "SOUTHEAST",
+	"SW": "SOUTHWEST",
+}
+
+// NormalizeCityName puts a city name in a form for comparing user-typed
+// names with City State names and their abbreviations: upper case, with
+// punctuation replaced by spaces, spaces collapsed and abbreviations such as
+// St, Ft and Mt expanded. "St. Louis", "Saint Louis" and "ST LOUIS" all
+// normalize to "SAINT LOUIS".
+func NormalizeCityName(name string) string {
+	return strings.Join(expandCityWords(splitCityName(name)), " ")
+}
+
+// NormalizeCityPrefix normalizes the start of a city name being typed, for
+// autocompletion. Its last word may be unfinished, so if expanding it
+// changes it the prefix is returned both expanded and as typed: "St" gives
+// "SAINT" and "ST", which also completes to STAFFORD.
+func NormalizeCityPrefix(prefix string) []string {
+	words := splitCityName(prefix)
+	expanded := expandCityWords(append([]string(nil), words...))
+	normalized := strings.Join(expanded, " ")
+
+	last := len(words) - 1
+	r, _ := utf8.DecodeLastRuneInString(prefix)
+	if last < 0 || isCitySeparator(r) || expanded[last] == words[last] {
+		return []string{normalized}
+	}
+	expanded[last] = words[last]
+	return []string{normalized, strings.Join(expanded, " ")}
+}
+
+func isCitySeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// splitCityName upper-cases a city name and splits it into words at
+// punctuation and spaces.
+func splitCityName(name string) []string {
+	return strings.FieldsFunc(strings.ToUpper(name), isCitySeparator)
+}
+
+// expandCityWords expands abbreviated words in place.
+func expandCityWords(words []string) []string {
+	for i, w := range words {
+		if i == 0 && len(words) > 1 {
+			if d, ok := cityDirections[w]; ok {
+				words[i] = d
+				continue
+			}
+		}
+		if e, ok := cityWords[w]; ok {
+			words[i] = e
+		}
+	}
+	return words
+}
@@ -0,0 +1,52 @@
+package citystate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeCityName(t *testing.T) {
+	tests := map[string]string{
+		"St Louis":          "SAINT LOUIS",
+		"St. Louis":         "SAINT LOUIS",
+		"saint  louis":      "SAINT LOUIS",
+		"Ft. Worth":         "FORT WORTH",
+		"Ft.Worth":          "FORT WORTH",
+		"Mt Pleasant":       "MOUNT PLEASANT",
+		"Ste. Genevieve":    "SAINTE GENEVIEVE",
+		"N Las Vegas":       "NORTH LAS VEGAS",
+		"Colorado Spgs":     "COLORADO SPRINGS",
+		"O'Fallon":          "O FALLON",
+		"Wilkes-Barre":      "WILKES BARRE",
+		"Port St. Lucie":    "PORT SAINT LUCIE",
+		"Sault Ste Marie":   "SAULT SAINTE MARIE",
+		"  Arlington,  VA ": "ARLINGTON VA",
+		"N":                 "N",
+		"":                  "",
+	}
+	for in, want := range tests {
+		if got := NormalizeCityName(in); got != want {
+			t.Errorf("%q: got %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNormalizeCityPrefix(t *testing.T) {
+	tests := map[string][]string{
+		"St":       {"SAINT", "ST"},
+		"st. ":     {"SAINT"},
+		"Ft. Wor":  {"FORT WOR"},
+		"Port St":  {"PORT SAINT", "PORT ST"},
+		"N":        {"N"},
+		"N Las":    {"NORTH LAS"},
+		"arl":      {"ARL"},
+		"":         {""},
+		"Mt. Pl ":  {"MOUNT PL"},
+		"Colo Spg": {"COLO SPRING", "COLO SPG"},
+	}
+	for in, want := range tests {
+		if got := NormalizeCityPrefix(in); !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: got %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package citystate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Status classifies a city, state and ZIP combination.
+type Status string
+
+const (
+	// StatusPreferred means the city is the ZIP's preferred last line name.
+	StatusPreferred Status = "preferred"
+	// StatusAcceptable means the city is a mailing name for the ZIP other
+	// than its preferred one.
+	StatusAcceptable Status = "acceptable"
+	// StatusNotAcceptable means the city isn't a mailing name for the ZIP:
+	// either the USPS marks it not to be used in mail, or it isn't a name
+	// for the ZIP at all.
+	StatusNotAcceptable Status = "not_acceptable"
+	// StatusWrongState means the ZIP is in another state.
+	StatusWrongState Status = "wrong_state"
+	// StatusUnknownZip means the ZIP isn't in the data or isn't a ZIP.
+	StatusUnknownZip Status = "unknown_zip"
+)
+
+// maxSuggestions limits the suggestions in a Validation.
+const maxSuggestions = 5
+
+var zipPattern = regexp.MustCompile(`^([0-9]{3,5})(-?[0-9]{4})?$`)
+
+// Records looks up the City State detail records a Validator checks
+// against.
+type Records interface {
+	// ZipRecords returns a ZIP's records, or none if the ZIP isn't in the
+	// data.
+	ZipRecords(zip string) ([]CityStateDetail, error)
+	// ZipsForCity returns the sorted ZIPs for which a normalized city name
+	// is a name in a state.
+	ZipsForCity(state, city string) ([]string, error)
+}
+
+// CitySearcher is implemented by Records that can find city names close to
+// a misspelled one. A Validator suggests them when a city has no ZIPs.
+type CitySearcher interface {
+	SearchCities(state, city string, limit int) ([]CityMatch, error)
+}
+
+// Suggestion is a city, state and ZIP that might be meant instead.
+type Suggestion struct {
+	City  string `json:"city"`
+	State string `json:"state"`
+	Zip   string `json:"zip"`
+}
+
+func (s Suggestion) String() string {
+	return fmt.Sprintf("%v %v %v", s.City, s.State, s.Zip)
+}
+
+// Validation is the result of checking a city, state and ZIP.
+type Validation struct {
+	Status Status `json:"status"`
+	// Zip is the ZIP checked: the first five digits of the ZIP given, with
+	// leading zeros restored.
+	Zip string `json:"zip_code,omitempty"`
+	// PreferredCity and StateAbbreviation are the ZIP's, if it's known.
+	PreferredCity     string `json:"preferred_city,omitempty"`
+	StateAbbreviation string `json:"state_abbreviation,omitempty"`
+	// Suggestions are corrections, most likely first. They're empty when
+	// the city is the ZIP's preferred name.
+	Suggestions []Suggestion `json:"suggestions,omitempty"`
+}
+
+// Validator classifies city, state and ZIP combinations.
+type Validator struct {
+	records Records
+}
+
+// NewValidator returns a Validator checking against records.
+func NewValidator(records Records) *Validator {
+	return &Validator{records: records}
+}
+
+// Validate classifies a city, state and ZIP. Cities are matched by name or
+// by their 13-character abbreviation after NormalizeCityName, so "St. Louis"
+// matches SAINT LOUIS. Only failures to look up records are errors.
+func (v *Validator) Validate(city, state, zip string) (Validation, error) {
+	city = NormalizeCityName(city)
+	state = strings.ToUpper(strings.TrimSpace(state))
+
+	var val Validation
+	var records []CityStateDetail
+	if zip, ok := normalizeZip(zip); ok {
+		val.Zip = zip
+		var err error
+		if records, err = v.records.ZipRecords(zip); err != nil {
+			return val, err
+		}
+	}
+
+	if len(records) == 0 {
+		val.Status = StatusUnknownZip
+		return val, v.suggestCityZips(&val, city, state)
+	}
+
+	preferred := records[0]
+	for _, r := range records {
+		if r.CityStateKey == r.PreferredLastLineCityStateKey {
+			preferred = r
+			break
+		}
+	}
+	val.PreferredCity = preferred.PreferredLastLineCityStateName
+	val.StateAbbreviation = preferred.StateAbbreviation
+
+	// match is the ZIP's record for the city, preferring a mailing name if
+	// the city matches more than one.
+	var match *CityStateDetail
+	for i, r := range records {
+		if city == "" || (city != NormalizeCityName(r.CityStateName) && city != NormalizeCityName(r.CityStateNameAbbreviation)) {
+			continue
+		}
+		if match == nil || (match.CityStateMailingNameIndicator != "Y" && r.CityStateMailingNameIndicator == "Y") {
+			match = &records[i]
+		}
+	}
+
+	switch {
+	case state != val.StateAbbreviation:
+		val.Status = StatusWrongState
+		name := val.PreferredCity
+		if match != nil && match.CityStateMailingNameIndicator == "Y" {
+			name = match.CityStateName
+		}
+		val.addSuggestion(Suggestion{name, val.StateAbbreviation, val.Zip})
+		return val, v.suggestCityZips(&val, city, state)
+
+	case match != nil && NormalizeCityName(match.CityStateName) == NormalizeCityName(val.PreferredCity):
+		val.Status = StatusPreferred
+		return val, nil
+
+	case match != nil && match.CityStateMailingNameIndicator == "Y":
+		val.Status = StatusAcceptable
+		val.addSuggestion(Suggestion{val.PreferredCity, val.StateAbbreviation, val.Zip})
+		return val, nil
+
+	default:
+		val.Status = StatusNotAcceptable
+		val.addSuggestion(Suggestion{val.PreferredCity, val.StateAbbreviation, val.Zip})
+		if match != nil {
+			return val, nil
+		}
+		// The city might be right and the ZIP wrong.
+		return val, v.suggestCityZips(&val, city, state)
+	}
+}
+
+// suggestCityZips suggests the ZIPs for which city is a name in state, or
+// for which similar names are if it's no name there.
+func (v *Validator) suggestCityZips(val *Validation, city, state string) error {
+	if city == "" || state == "" {
+		return nil
+	}
+	zips, err := v.records.ZipsForCity(state, city)
+	if err != nil {
+		return err
+	}
+	for _, zip := range zips {
+		val.addSuggestion(Suggestion{city, state, zip})
+	}
+
+	searcher, ok := v.records.(CitySearcher)
+	if len(zips) > 0 || !ok {
+		return nil
+	}
+	matches, err := searcher.SearchCities(state, city, maxSuggestions)
+	if err != nil {
+		return err
+	}
+	for _, m := range matches {
+		for _, zip := range m.Zips {
+			val.addSuggestion(Suggestion{m.Name, state, zip})
+		}
+	}
+	return nil
+}
+
+func (val *Validation) addSuggestion(s Suggestion) {
+	if len(val.Suggestions) >= maxSuggestions {
+		return
+	}
+	for _, t := range val.Suggestions {
+		if t == s {
+			return
+		}
+	}
+	val.Suggestions = append(val.Suggestions, s)
+}
+
+// normalizeZip returns the five-digit ZIP of a ZIP or ZIP+4, restoring
+// leading zeros dropped by spreadsheets.
+func normalizeZip(zip string) (string, bool) {
+	m := zipPattern.FindStringSubmatch(strings.TrimSpace(zip))
+	if m == nil || (m[2] != "" && len(m[1]) != 5) {
+		return "", false
+	}
+	return strings.Repeat("0", 5-len(m[1])) + m[1], true
+}
+
+type memoryRecords struct {
+	zips  map[string][]CityStateDetail
+	index *CityIndex
+}
+
+// NewMemoryRecords reads detail records into memory, for example from
+// ReadCityStateFile. They implement CitySearcher.
+func NewMemoryRecords(read func(yield func(CityStateDetail)) error) (Records, error) {
+	r := &memoryRecords{zips: make(map[string][]CityStateDetail), index: NewCityIndex()}
+	err := read(func(d CityStateDetail) {
+		r.zips[d.ZipCode] = append(r.zips[d.ZipCode], d)
+		r.index.Add(d)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *memoryRecords) ZipRecords(zip string) ([]CityStateDetail, error) {
+	return r.zips[zip], nil
+}
+
+func (r *memoryRecords) ZipsForCity(state, city string) ([]string, error) {
+	return r.index.Zips(state, city), nil
+}
+
+func (r *memoryRecords) SearchCities(state, city string, limit int) ([]CityMatch, error) {
+	return r.index.Search(state, city, limit), nil
+}
@@ -0,0 +1,89 @@
+package citystate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testRecords(t *testing.T) Records {
+	details := []CityStateDetail{
+		{ZipCode: "63101", CityStateKey: "K00001", CityStateName: "SAINT LOUIS", CityStateNameAbbreviation: "ST LOUIS", CityStateMailingNameIndicator: "Y"},
+		{ZipCode: "63101", CityStateKey: "K00002", CityStateName: "DOWNTOWN", CityStateMailingNameIndicator: "Y"},
+		{ZipCode: "63101", CityStateKey: "K00003", CityStateName: "SAINT LOUIS CITY", CityStateMailingNameIndicator: "N"},
+		{ZipCode: "63102", CityStateKey: "K00001", CityStateName: "SAINT LOUIS", CityStateNameAbbreviation: "ST LOUIS", CityStateMailingNameIndicator: "Y"},
+		{ZipCode: "01001", CityStateKey: "K00010", CityStateName: "AGAWAM", CityStateMailingNameIndicator: "Y"},
+	}
+	records, err := NewMemoryRecords(func(yield func(CityStateDetail)) error {
+		for _, d := range details {
+			d.PreferredLastLineCityStateKey = d.CityStateKey
+			d.PreferredLastLineCityStateName = d.CityStateName
+			d.StateAbbreviation = "MO"
+			if d.ZipCode == "63101" {
+				d.PreferredLastLineCityStateKey = "K00001"
+				d.PreferredLastLineCityStateName = "SAINT LOUIS"
+			}
+			if d.ZipCode == "01001" {
+				d.StateAbbreviation = "MA"
+			}
+			yield(d)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func TestValidate(t *testing.T) {
+	v := NewValidator(testRecords(t))
+
+	tests := []struct {
+		city, state, zip string
+		status           Status
+		suggestions      []Suggestion
+	}{
+		{"Saint Louis", "mo", "63101", StatusPreferred, nil},
+		{"st  louis", "MO", "63101-1234", StatusPreferred, nil},
+		{"Downtown", "MO", "63101", StatusAcceptable, []Suggestion{{"SAINT LOUIS", "MO", "63101"}}},
+		{"Saint Louis City", "MO", "63101", StatusNotAcceptable, []Suggestion{{"SAINT LOUIS", "MO", "63101"}}},
+		{"Downtown", "MO", "63102", StatusNotAcceptable, []Suggestion{{"SAINT LOUIS", "MO", "63102"}, {"DOWNTOWN", "MO", "63101"}}},
+		{"Saint Louis", "IL", "63101", StatusWrongState, []Suggestion{{"SAINT LOUIS", "MO", "63101"}}},
+		{"Saint Louis", "MO", "63199", StatusUnknownZip, []Suggestion{{"SAINT LOUIS", "MO", "63101"}, {"SAINT LOUIS", "MO", "63102"}}},
+		{"Saint Louis", "MO", "not a zip", StatusUnknownZip, []Suggestion{{"SAINT LOUIS", "MO", "63101"}, {"SAINT LOUIS", "MO", "63102"}}},
+		{"Agawam", "MA", "1001", StatusPreferred, nil},
+		{"St. Louis", "MO", "63101", StatusPreferred, nil},
+		{"Saint Lious", "MO", "63199", StatusUnknownZip, []Suggestion{{"SAINT LOUIS", "MO", "63101"}, {"SAINT LOUIS", "MO", "63102"}}},
+		{"Dowtown", "MO", "63102", StatusNotAcceptable, []Suggestion{{"SAINT LOUIS", "MO", "63102"}, {"DOWNTOWN", "MO", "63101"}}},
+	}
+
+	for _, test := range tests {
+		val, err := v.Validate(test.city, test.state, test.zip)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if val.Status != test.status || !reflect.DeepEqual(val.Suggestions, test.suggestions) {
+			t.Errorf("%v, %v %v: got %v %v, want %v %v", test.city, test.state, test.zip, val.Status, val.Suggestions, test.status, test.suggestions)
+		}
+	}
+}
+
+func TestNormalizeZip(t *testing.T) {
+	tests := map[string]string{
+		"22201":      "22201",
+		" 22201 ":    "22201",
+		"22201-1234": "22201",
+		"222011234":  "22201",
+		"501":        "00501",
+		"2220":       "02220",
+		"2220-1234":  "",
+		"22":         "",
+		"abcde":      "",
+	}
+	for in, want := range tests {
+		got, _ := normalizeZip(in)
+		if got != want {
+			t.Errorf("%q: got %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/corbaltcode/usps/citystate"
+	"github.com/corbaltcode/usps/export"
+	"github.com/corbaltcode/usps/zip4"
+)
+
+func main() {
+	tarName := flag.String("tar", "zip4natl.tar", "Name of the zip4natl tar file")
+	format := flag.String("format", "parquet", "Output format: parquet, csv or ndjson")
+	outDir := flag.String("out", ".", "Directory to write files to")
+	datasets := flag.String("datasets", "zip4,citystate", "Comma-separated datasets to export: zip4, citystate")
+	partition := flag.Bool("partition-by-state", false, "Write a file per state, in state=XX directories")
+	rowGroupSize := flag.Int64("row-group-size", export.DefaultRowGroupSize, "Rows per Parquet row group")
+	flag.Parse()
+
+	opts := export.Options{
+		Format:           export.Format(*format),
+		Dir:              *outDir,
+		PartitionByState: *partition,
+		RowGroupSize:     *rowGroupSize,
+	}
+	if opts.Format != export.Parquet && opts.Format != export.CSV && opts.Format != export.NDJSON {
+		fmt.Fprintf(os.Stderr, "Error: Unknown format %q\n", *format)
+		os.Exit(1)
+	}
+
+	for _, dataset := range strings.Split(*datasets, ",") {
+		var files []string
+		var records int
+		var err error
+
+		switch strings.TrimSpace(dataset) {
+		case "zip4":
+			password := mustGetenv("ZIP4_PWD")
+			files, records, err = export.ExportZip4(func(yield func(zip4.Zip4Detail)) error {
+				return zip4.ReadZip4FromZip4Tar(*tarName, password, yield)
+			}, opts)
+		case "citystate":
+			password := mustGetenv("CITYSTATE_PWD")
+			files, records, err = export.ExportCityState(func(yield func(citystate.CityStateDetail)) error {
+				return zip4.ReadCityStateFromZip4Tar(*tarName, password, yield)
+			}, opts)
+		default:
+			fmt.Fprintf(os.Stderr, "Error: Unknown dataset %q\n", dataset)
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to export %v: %v\n", dataset, err)
+			os.Exit(1)
+		}
+
+		log.Printf("Exported %v %v records to %v files.\n", records, dataset, len(files))
+	}
+}
+
+func mustGetenv(key string) string {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		panic(fmt.Sprintf("missing env var: %v", key))
+	}
+	return v
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/corbaltcode/usps/releasediff"
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+)
+
+func main() {
+	oldName := flag.String("old", "", "Old release: a zip4natl tar or a seed-db database")
+	newName := flag.String("new", "", "New release: a zip4natl tar or a seed-db database")
+	changesetName := flag.String("changeset", "", "File to write the JSON changeset to")
+	examples := flag.Int("examples", 20, "Number of example changes of each kind to list in the summary")
+	flag.Parse()
+
+	if *oldName == "" || *newName == "" {
+		fmt.Fprintf(os.Stderr, "Error: Missing required parameters\nUsage: %s -old <tar_or_db> -new <tar_or_db> [-changeset <file>] [-examples <n>]\n", os.Args[0])
+		os.Exit(1)
+	}
+
+	oldRelease, err := load(*oldName, "OLD_")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load old release: %v\n", err)
+		os.Exit(1)
+	}
+
+	newRelease, err := load(*newName, "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load new release: %v\n", err)
+		os.Exit(1)
+	}
+
+	log.Printf("Comparing %v ZIPs to %v ZIPs...\n", len(oldRelease.Zips), len(newRelease.Zips))
+	changeset := releasediff.Diff(oldRelease, newRelease)
+
+	if *changesetName != "" {
+		f, err := os.Create(*changesetName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create changeset file: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(changeset); err != nil {
+			log.Fatalf("Error writing changeset: %v", err)
+		}
+	}
+
+	if err := changeset.WriteSummary(os.Stdout, *examples); err != nil {
+		log.Fatalf("Error writing summary: %v", err)
+	}
+}
+
+// load reads a release from a tar, or from a seed-db database if the file
+// isn't a tar. A tar's ZIP+4 and City State passwords come from ZIP4_PWD and
+// CITYSTATE_PWD; passwords can change from release to release, so envPrefix
+// names variables that override them, such as OLD_ZIP4_PWD.
+func load(fileName, envPrefix string) (*releasediff.Snapshot, error) {
+	log.Printf("Loading release from %v...\n", fileName)
+
+	if strings.HasSuffix(fileName, ".tar") {
+		return releasediff.LoadTar(fileName, password(envPrefix, "ZIP4_PWD"), password(envPrefix, "CITYSTATE_PWD"))
+	}
+
+	db, err := sql.Open("sqlite3", fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	return releasediff.LoadDatabase(db)
+}
+
+// password returns the env var prefix+key if it's set, otherwise key.
+func password(prefix, key string) string {
+	if v, ok := os.LookupEnv(prefix + key); ok {
+		return v
+	}
+	return mustGetenv(key)
+}
+
+func mustGetenv(key string) string {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		panic(fmt.Sprintf("missing env var: %v", key))
+	}
+	return v
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/corbaltcode/usps/citystate"
+	"github.com/corbaltcode/usps/internal/storage"
+	"github.com/corbaltcode/usps/zip4"
+)
+
+// ChangeFiles names the change files to apply. Either file name may be
+// empty; passwords are only needed for zips.
+type ChangeFiles struct {
+	Zip4File          string
+	Zip4Password      string
+	CityStateFile     string
+	CityStatePassword string
+}
+
+// ApplyChanges applies ZIP+4 and City State change files to a database at
+// baseRelease in one transaction, leaving it at release.
+func ApplyChanges(store storage.Store, files ChangeFiles, baseRelease, release string) error {
+	var changes storage.Changes
+
+	if files.Zip4File != "" {
+		log.Printf("Applying ZIP+4 changes from %v...\n", files.Zip4File)
+		changes.Zip4File = filepath.Base(files.Zip4File)
+		changes.ReadZip4 = func(yield func(zip4.Zip4Detail)) error {
+			return readZip4Changes(files.Zip4File, files.Zip4Password, yield)
+		}
+	}
+
+	if files.CityStateFile != "" {
+		log.Printf("Applying City State changes from %v...\n", files.CityStateFile)
+		changes.CityStateFile = filepath.Base(files.CityStateFile)
+		changes.ReadCityState = func(yield func(citystate.CityStateDetail)) error {
+			return readCityStateChanges(files.CityStateFile, files.CityStatePassword, yield)
+		}
+	}
+
+	return store.ApplyChanges(changes, baseRelease, release)
+}
+
+func readZip4Changes(fileName, password string, yield func(zip4.Zip4Detail)) error {
+	if isZip(fileName) {
+		return zip4.ReadZip4FromZip(fileName, password, yield)
+	}
+	return readFile(fileName, func(r io.Reader) error {
+		return zip4.ReadZip4File(r, yield)
+	})
+}
+
+func readCityStateChanges(fileName, password string, yield func(citystate.CityStateDetail)) error {
+	if isZip(fileName) {
+		return zip4.ReadCityStateFromZip(fileName, password, yield)
+	}
+	return readFile(fileName, func(r io.Reader) error {
+		return citystate.ReadCityStateFile(r, yield)
+	})
+}
+
+func isZip(fileName string) bool {
+	return strings.EqualFold(filepath.Ext(fileName), ".zip")
+}
+
+func readFile(fileName string, read func(io.Reader) error) error {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return read(f)
+}
@@ -1,212 +1,298 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
-	"database/sql"
-	"github.com/corbaltcode/usps/zip4"
+	"strings"
+
 	"github.com/corbaltcode/usps/citystate"
-	_ "github.com/mattn/go-sqlite3" // sqlite driver
+	"github.com/corbaltcode/usps/internal/storage"
+	"github.com/corbaltcode/usps/zip4"
 )
 
 const BATCH_SIZE = 500000
 
-const zip4CreateTableQuery = `CREATE TABLE IF NOT EXISTS zip4_data(
-								ZipCode TEXT NOT NULL,
-								RecordTypeCode TEXT,
-								StateAbbreviation TEXT,
-								CountyNumber TEXT,
-								Plus4LowNumber TEXT,
-								Plus4HighNumber TEXT)`
-const zip4InsertQuery = `INSERT INTO zip4_data(ZipCode,RecordTypeCode,StateAbbreviation,CountyNumber,Plus4LowNumber,Plus4HighNumber) VALUES(?,?,?,?,?,?)`
-
-
-const citystateCreateTableQuery = `CREATE TABLE IF NOT EXISTS city_state(
-									CopyrightDetailCode TEXT,
-									ZipCode TEXT NOT NULL,
-									CityStateKey TEXT,
-									ZipClassificationCode TEXT,
-									CityStateName TEXT,
-									CityStateNameAbbreviation TEXT,
-									CityStateNameFacilityCode TEXT,
-									CityStateMailingNameIndicator TEXT,
-									PreferredLastLineCityStateKey TEXT,
-									PreferredLastLineCityStateName TEXT,
-									CityDeliveryIndicator TEXT,
-									CarrierRouteRateSortation TEXT,
-									UniqueZipNameIndicator TEXT,
-									FinanceNumber TEXT,
-									StateAbbreviation TEXT,
-									CountyNumber TEXT,
-									CountyName TEXT)`
-const citystateInsertQuery = `INSERT INTO city_state(CopyrightDetailCode,ZipCode,CityStateKey,ZipClassificationCode,CityStateName,CityStateNameAbbreviation,CityStateNameFacilityCode,CityStateMailingNameIndicator,PreferredLastLineCityStateKey,PreferredLastLineCityStateName,CityDeliveryIndicator,CarrierRouteRateSortation,UniqueZipNameIndicator,FinanceNumber,StateAbbreviation,CountyNumber,CountyName) VALUES(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`
+// SeedOptions controls a full load from a zip4natl tar.
+type SeedOptions struct {
+	TarName string
+	// Mode says what to do when a dataset is already loaded.
+	Mode storage.Mode
+	// States, if not nil, limits records to these state abbreviations.
+	States map[string]bool
+	// BatchSize is the number of rows inserted per transaction.
+	BatchSize int
+	// Release, if set, is recorded in load_metadata.
+	Release string
+}
+
+func (o SeedOptions) includeState(abbr string) bool {
+	return o.States == nil || o.States[abbr]
+}
 
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Fprintf(os.Stderr, "usage: %v <db-name>\n", filepath.Base(os.Args[0]))
-		os.Exit(1)
+	tarName := flag.String("tar", "zip4natl.tar", "Name of the zip4natl tar file")
+	passwordEnv := flag.String("password-env", "", "Env var holding the password for both datasets (default ZIP4_PWD and CITYSTATE_PWD)")
+	passwordFile := flag.String("password-file", "", "File holding the password for both datasets")
+	datasets := flag.String("datasets", "zip4,citystate", "Comma-separated datasets to load: zip4, citystate")
+	mode := flag.String("mode", "fail", "What to do if a dataset is already loaded: replace, append or fail")
+	states := flag.String("states", "", "Comma-separated state abbreviations to load (default all)")
+	batchSize := flag.Int("batch-size", BATCH_SIZE, "Rows inserted per transaction")
+	release := flag.String("release", "", "Release being loaded or applied, e.g. 2026-10; recorded in the database")
+	baseRelease := flag.String("base-release", "", "Release the database must be at before applying change files")
+	applyZip4 := flag.String("apply-zip4", "", "ZIP+4 change file (.txt or .zip) to apply to an existing database")
+	applyCityState := flag.String("apply-citystate", "", "City State change file (.txt or .zip) to apply to an existing database")
+	atomic := flag.Bool("atomic", false, "Build a new database beside the existing one, validate it and swap it in")
+	keep := flag.Int("keep", 2, "Previous versions to keep when swapping in a database built with -atomic")
+	rollback := flag.Bool("rollback", false, "Swap the most recent previous version back in and exit")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %v [flags] <db-name-or-dsn>\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(os.Stderr, "A postgres:// or postgresql:// DSN loads PostgreSQL; anything else is a SQLite file.\n")
+		flag.PrintDefaults()
 	}
+	flag.Parse()
 
-	dbName := os.Args[1]
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
 
-	db, err := sql.Open("sqlite3", dbName)
-	if err != nil {
-		panic(err)
+	m := storage.Mode(*mode)
+	if m != storage.ModeReplace && m != storage.ModeAppend && m != storage.ModeFail {
+		fmt.Fprintf(os.Stderr, "Error: Unknown mode %q\n", *mode)
+		os.Exit(1)
 	}
-	defer db.Close()
 
-	err = SeedZip4Data(db)
-	if err != nil {
-		panic(err)
+	loadZip4, loadCityState := false, false
+	for _, d := range strings.Split(*datasets, ",") {
+		switch storage.Dataset(strings.TrimSpace(d)) {
+		case storage.Zip4Dataset:
+			loadZip4 = true
+		case storage.CityStateDataset:
+			loadCityState = true
+		default:
+			fmt.Fprintf(os.Stderr, "Error: Unknown dataset %q\n", d)
+			os.Exit(1)
+		}
 	}
 
-	err = SeedCityStateData(db)
-	if err != nil {
-		panic(err)
+	opts := SeedOptions{TarName: *tarName, Mode: m, BatchSize: *batchSize, Release: *release}
+	if *states != "" {
+		opts.States = make(map[string]bool)
+		for _, state := range strings.Split(*states, ",") {
+			opts.States[strings.ToUpper(strings.TrimSpace(state))] = true
+		}
 	}
-}
 
-func SeedZip4Data(db *sql.DB) error {
-	var zip4Data []zip4.Zip4Detail
-	_, err := db.Exec(zip4CreateTableQuery)
-	if err != nil {
-		return err
+	password := func(legacyEnv string) string {
+		pwd, err := getPassword(*passwordEnv, *passwordFile, legacyEnv)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return pwd
 	}
 
-	tx, err := db.Begin()
-	if err != nil {
-		return err
+	if *rollback {
+		err := storage.Rollback(flag.Arg(0))
+		if err != nil {
+			panic(err)
+		}
+		return
 	}
 
-	err = zip4.ReadZip4FromZip4Tar("zip4natl.tar", mustGetenv("ZIP4_PWD"), func(detail zip4.Zip4Detail){
-		zip4Data = append(zip4Data, detail)
-		if len(zip4Data) >= BATCH_SIZE {
-			for i := 0; i < len(zip4Data); i++ {
-				params := getParameters(zip4Data[i])
-				_, err = tx.Exec(zip4InsertQuery, params...)
-				if err != nil {
-					panic(err)
-				}
-			}
+	if *applyZip4 != "" || *applyCityState != "" {
+		if *atomic {
+			fmt.Fprintf(os.Stderr, "Error: change files are already applied in one transaction; -atomic is for full loads\n")
+			os.Exit(1)
+		}
+		if *baseRelease == "" || *release == "" {
+			fmt.Fprintf(os.Stderr, "Error: applying change files requires -base-release and -release\n")
+			os.Exit(1)
+		}
 
-			zip4Data = []zip4.Zip4Detail{}
+		changes := ChangeFiles{Zip4File: *applyZip4, CityStateFile: *applyCityState}
+		if isZip(changes.Zip4File) {
+			changes.Zip4Password = password("ZIP4_PWD")
 		}
-	})
-	if err != nil {
-		return err
+		if isZip(changes.CityStateFile) {
+			changes.CityStatePassword = password("CITYSTATE_PWD")
+		}
+
+		store, err := storage.Open(flag.Arg(0))
+		if err != nil {
+			panic(err)
+		}
+		defer store.Close()
+
+		err = store.Migrate()
+		if err != nil {
+			panic(err)
+		}
+
+		err = ApplyChanges(store, changes, *baseRelease, *release)
+		if err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	var zip4Password, cityStatePassword string
+	if loadZip4 {
+		zip4Password = password("ZIP4_PWD")
 	}
+	if loadCityState {
+		cityStatePassword = password("CITYSTATE_PWD")
+	}
+
+	seed := func(store storage.Store) error {
+		err := store.Migrate()
+		if err != nil {
+			return err
+		}
+
+		err = store.TuneForBulkLoad()
+		if err != nil {
+			return err
+		}
 
-	if len(zip4Data) != 0 {
-		for i := 0; i < len(zip4Data); i++ {
-			params := getParameters(zip4Data[i])
-			_, err = tx.Exec(zip4InsertQuery, params...)
+		if loadZip4 {
+			err = SeedZip4Data(store, zip4Password, opts)
 			if err != nil {
 				return err
 			}
 		}
+
+		if loadCityState {
+			err = SeedCityStateData(store, cityStatePassword, opts)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
-	err = tx.Commit()
+	if *atomic {
+		staging, err := storage.OpenStaging(flag.Arg(0))
+		if err != nil {
+			panic(err)
+		}
+		defer staging.Close()
+
+		err = seed(staging)
+		if err != nil {
+			panic(err)
+		}
+
+		err = staging.Validate()
+		if err != nil {
+			panic(err)
+		}
+
+		err = staging.Promote(*keep)
+		if err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	store, err := storage.Open(flag.Arg(0))
 	if err != nil {
-		return err
+		panic(err)
 	}
+	defer store.Close()
 
-	return nil
+	err = seed(store)
+	if err != nil {
+		panic(err)
+	}
 }
 
-func SeedCityStateData(db *sql.DB) error {
-	var citystateData []citystate.CityStateDetail
+// getPassword reads the password from passwordFile or passwordEnv if set,
+// falling back to legacyEnv.
+func getPassword(passwordEnv, passwordFile, legacyEnv string) (string, error) {
+	if passwordFile != "" {
+		bz, err := os.ReadFile(passwordFile)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(bz), "\r\n"), nil
+	}
 
-	_, err := db.Exec(citystateCreateTableQuery)
+	env := legacyEnv
+	if passwordEnv != "" {
+		env = passwordEnv
+	}
+	v, ok := os.LookupEnv(env)
+	if !ok {
+		return "", fmt.Errorf("missing env var: %v", env)
+	}
+	return v, nil
+}
+
+func SeedZip4Data(store storage.Store, password string, opts SeedOptions) error {
+	header, err := zip4.ReadZip4Header(opts.TarName, password)
 	if err != nil {
 		return err
 	}
 
-	tx, err := db.Begin()
+	err = store.PrepareDataset(storage.Zip4Dataset, opts.Mode)
 	if err != nil {
 		return err
 	}
 
-	err = zip4.ReadCityStateFromZip4Tar("zip4natl.tar", mustGetenv("CITYSTATE_PWD"), func(detail citystate.CityStateDetail){
-		citystateData = append(citystateData, detail)
-		if len(citystateData) >= BATCH_SIZE {
-			for i := 0; i < len(citystateData); i++ {
-				params := getParameters(citystateData[i])
-				_, err = tx.Exec(citystateInsertQuery, params...)
-				if err != nil {
-					panic(err)
-				}
+	n, err := store.LoadZip4(func(yield func(zip4.Zip4Detail)) error {
+		return zip4.ReadZip4FromZip4Tar(opts.TarName, password, func(detail zip4.Zip4Detail) {
+			if opts.includeState(detail.StateAbbreviation) {
+				yield(detail)
 			}
-
-			citystateData = []citystate.CityStateDetail{}
-		}
-		
-	})
+		})
+	}, opts.BatchSize)
 	if err != nil {
 		return err
 	}
 
-	if len(citystateData) != 0 {
-		for i := 0; i < len(citystateData); i++ {
-			params := getParameters(citystateData[i])
-			_, err = tx.Exec(citystateInsertQuery, params...)
-			if err != nil {
-				return err
-			}
-		}
+	return recordLoad(store, storage.Zip4Dataset, n, header, opts)
+}
+
+func SeedCityStateData(store storage.Store, password string, opts SeedOptions) error {
+	header, err := zip4.ReadCityStateHeader(opts.TarName, password)
+	if err != nil {
+		return err
 	}
 
-	err = tx.Commit()
+	err = store.PrepareDataset(storage.CityStateDataset, opts.Mode)
 	if err != nil {
 		return err
 	}
 
-	return nil
-}
+	n, err := store.LoadCityState(func(yield func(citystate.CityStateDetail)) error {
+		return zip4.ReadCityStateFromZip4Tar(opts.TarName, password, func(detail citystate.CityStateDetail) {
+			if opts.includeState(detail.StateAbbreviation) {
+				yield(detail)
+			}
+		})
+	}, opts.BatchSize)
+	if err != nil {
+		return err
+	}
 
-func getParameters(detail any) []any {
-	switch params := detail.(type) {
-	case citystate.CityStateDetail:
-		val := []any {
-			params.CopyrightDetailCode,
-			params.ZipCode,
-			params.CityStateKey,
-			params.ZipClassificationCode,
-			params.CityStateName,
-			params.CityStateNameAbbreviation,
-			params.CityStateNameFacilityCode,
-			params.CityStateMailingNameIndicator,
-			params.PreferredLastLineCityStateKey,
-			params.PreferredLastLineCityStateName,
-			params.CityDeliveryIndicator,
-			params.CarrierRouteRateSortation,
-			params.UniqueZipNameIndicator,
-			params.FinanceNumber,
-			params.StateAbbreviation,
-			params.CountyNumber,
-			params.CountyName,
-		}
-		return val
-	case zip4.Zip4Detail:
-		val := []any {
-			params.ZipCode,
-			params.RecordTypeCode,
-			params.StateAbbreviation,
-			params.CountyNumber,
-			params.Plus4LowNumber,
-			params.Plus4HighNumber,
-		}
-		return val
-	}
-
-	return nil
+	return recordLoad(store, storage.CityStateDataset, n, header, opts)
 }
 
-func mustGetenv(key string) string {
-	v, ok := os.LookupEnv(key)
-	if !ok {
-		panic(fmt.Sprintf("missing env var: %v", key))
+func recordLoad(store storage.Store, dataset storage.Dataset, records int, header zip4.Header, opts SeedOptions) error {
+	// The header counts every state's records.
+	expected := header.RecordCount
+	if opts.States != nil {
+		expected = 0
 	}
-	return v
-}
\ No newline at end of file
+	return store.RecordLoad(storage.LoadMetadata{
+		Dataset:             string(dataset),
+		SourceFile:          filepath.Base(opts.TarName),
+		FulfillmentDate:     header.FileVersion,
+		RecordCount:         records,
+		ExpectedRecordCount: expected,
+		Release:             opts.Release,
+	})
+}
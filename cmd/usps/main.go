@@ -0,0 +1,20 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var commands = map[string]func(args []string){
+	"serve":    serve,
+	"validate": validate,
+}
+
+func main() {
+	if len(os.Args) < 2 || commands[os.Args[1]] == nil {
+		fmt.Fprintf(os.Stderr, "usage: %v <command> [flags]\n\ncommands:\n  serve     serve lookups over HTTP\n  validate  validate the cities, states and ZIPs in a CSV file\n", filepath.Base(os.Args[0]))
+		os.Exit(1)
+	}
+	commands[os.Args[1]](os.Args[2:])
+}
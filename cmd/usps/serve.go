@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/corbaltcode/usps/lookup"
+	"github.com/corbaltcode/usps/server"
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// shutdownTimeout is how long in-flight requests get to finish.
+const shutdownTimeout = 30 * time.Second
+
+func serve(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", ":8080", "Address to listen on")
+	grpcAddr := flags.String("grpc-addr", "", "Address to serve the gRPC LookupService on (disabled if empty)")
+	dbName := flags.String("db", "", "seed-db SQLite database to serve; reopened on SIGHUP")
+	tarName := flags.String("tar", "", "zip4natl tar to load into memory and serve instead of a database")
+	flags.Parse(args)
+
+	if (*dbName == "") == (*tarName == "") {
+		fmt.Fprintf(os.Stderr, "Error: exactly one of -db and -tar is required\n")
+		os.Exit(1)
+	}
+
+	s := server.New()
+	httpServer := &http.Server{Addr: *addr, Handler: s}
+
+	var grpcServer *grpc.Server
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	if *grpcAddr != "" {
+		lis, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		grpcServer = grpc.NewServer(s.GRPCOptions()...)
+		s.RegisterGRPC(grpcServer)
+		healthpb.RegisterHealthServer(grpcServer, healthServer)
+		reflection.Register(grpcServer)
+		go func() {
+			log.Printf("Serving gRPC on %v\n", *grpcAddr)
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Fatal(err)
+			}
+		}()
+	}
+
+	// load is called at startup and on SIGHUP.
+	var loadMu sync.Mutex
+	load := func() error {
+		loadMu.Lock()
+		defer loadMu.Unlock()
+
+		if *tarName != "" {
+			store, err := lookup.LoadTar(*tarName, mustGetenv("ZIP4_PWD"), mustGetenv("CITYSTATE_PWD"))
+			if err != nil {
+				return err
+			}
+			return s.SetStore(store)
+		}
+
+		db, err := sql.Open("sqlite3", "file:"+*dbName+"?mode=ro")
+		if err != nil {
+			return err
+		}
+		// The server closes the old database once the requests that got it
+		// before the swap have finished.
+		if err := s.SetStore(dbStore{lookup.NewSQLiteStore(db), db}); err != nil {
+			db.Close()
+			return err
+		}
+		return nil
+	}
+
+	// Loading a tar takes a while; the server reports not ready until it's
+	// done.
+	go func() {
+		if err := load(); err != nil {
+			log.Fatalf("Failed to load data: %v", err)
+		}
+		healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+		log.Printf("Serving data.\n")
+	}()
+
+	// stopped is closed once in-flight requests have finished after
+	// SIGINT or SIGTERM.
+	stopped := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		for sig := range signals {
+			if sig == syscall.SIGHUP {
+				if *dbName == "" {
+					continue
+				}
+				if err := load(); err != nil {
+					log.Printf("Failed to reopen %v: %v\n", *dbName, err)
+				} else {
+					log.Printf("Reopened %v.\n", *dbName)
+				}
+				continue
+			}
+
+			healthServer.Shutdown()
+			if grpcServer != nil {
+				// GracefulStop waits for streams too, so it's bounded like
+				// the HTTP shutdown.
+				timer := time.AfterFunc(shutdownTimeout, grpcServer.Stop)
+				grpcServer.GracefulStop()
+				timer.Stop()
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			httpServer.Shutdown(ctx)
+			cancel()
+			close(stopped)
+			return
+		}
+	}()
+
+	log.Printf("Listening on %v\n", *addr)
+	// ListenAndServe returns as soon as Shutdown starts, so wait for it.
+	err := httpServer.ListenAndServe()
+	if !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+	<-stopped
+}
+
+// dbStore is a SQLite store that closes its database when the server is
+// done with it.
+type dbStore struct {
+	lookup.Store
+	db *sql.DB
+}
+
+func (s dbStore) Close() error {
+	return s.db.Close()
+}
+
+func mustGetenv(key string) string {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		panic(fmt.Sprintf("missing env var: %v", key))
+	}
+	return v
+}
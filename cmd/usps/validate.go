@@ -0,0 +1,139 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/corbaltcode/usps/citystate"
+	"github.com/corbaltcode/usps/lookup"
+	"github.com/corbaltcode/usps/zip4"
+)
+
+// validateColumns are appended to each input row.
+var validateColumns = []string{"status", "preferred_city", "zip_state", "suggestions"}
+
+func validate(args []string) {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	dbName := flags.String("db", "", "seed-db SQLite database to validate against")
+	tarName := flags.String("tar", "", "zip4natl tar whose City State data to validate against instead of a database")
+	inName := flags.String("in", "", "CSV file to validate, with a header row (default stdin)")
+	outName := flags.String("out", "", "CSV file to write (default stdout)")
+	cityColumn := flags.String("city-column", "city", "Header of the city column")
+	stateColumn := flags.String("state-column", "state", "Header of the state column")
+	zipColumn := flags.String("zip-column", "zip", "Header of the ZIP column")
+	flags.Parse(args)
+
+	if (*dbName == "") == (*tarName == "") {
+		fmt.Fprintf(os.Stderr, "Error: exactly one of -db and -tar is required\n")
+		os.Exit(1)
+	}
+
+	var records citystate.Records
+	var err error
+	if *tarName != "" {
+		records, err = citystate.NewMemoryRecords(func(yield func(citystate.CityStateDetail)) error {
+			return zip4.ReadCityStateFromZip4Tar(*tarName, mustGetenv("CITYSTATE_PWD"), yield)
+		})
+	} else {
+		var db *sql.DB
+		db, err = sql.Open("sqlite3", "file:"+*dbName+"?mode=ro")
+		if err == nil {
+			defer db.Close()
+			records = lookup.CityStateRecords(lookup.NewSQLiteStore(db))
+		}
+	}
+	if err != nil {
+		log.Fatalf("Failed to load City State data: %v", err)
+	}
+
+	in := os.Stdin
+	if *inName != "" {
+		if in, err = os.Open(*inName); err != nil {
+			log.Fatal(err)
+		}
+		defer in.Close()
+	}
+	out := os.Stdout
+	if *outName != "" {
+		if out, err = os.Create(*outName); err != nil {
+			log.Fatal(err)
+		}
+		defer out.Close()
+	}
+
+	counts, err := validateCSV(citystate.NewValidator(records), in, out, *cityColumn, *stateColumn, *zipColumn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, status := range []citystate.Status{citystate.StatusPreferred, citystate.StatusAcceptable, citystate.StatusNotAcceptable, citystate.StatusWrongState, citystate.StatusUnknownZip} {
+		log.Printf("%v: %v\n", status, counts[status])
+	}
+}
+
+// validateCSV copies CSV rows from r to w with each row's validation
+// appended, and counts the rows by status.
+func validateCSV(v *citystate.Validator, r io.Reader, w io.Writer, cityColumn, stateColumn, zipColumn string) (map[citystate.Status]int, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cw := csv.NewWriter(w)
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading header: %v", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	var indexes [3]int
+	for i, name := range []string{cityColumn, stateColumn, zipColumn} {
+		index, ok := columns[name]
+		if !ok {
+			return nil, fmt.Errorf("no %q column", name)
+		}
+		indexes[i] = index
+	}
+	if err := cw.Write(append(header, validateColumns...)); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[citystate.Status]int)
+	field := func(row []string, i int) string {
+		if i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := v.Validate(field(row, indexes[0]), field(row, indexes[1]), field(row, indexes[2]))
+		if err != nil {
+			return nil, err
+		}
+		counts[val.Status]++
+
+		suggestions := make([]string, len(val.Suggestions))
+		for i, s := range val.Suggestions {
+			suggestions[i] = s.String()
+		}
+		if err := cw.Write(append(row, string(val.Status), val.PreferredCity, val.StateAbbreviation, strings.Join(suggestions, "; "))); err != nil {
+			return nil, err
+		}
+	}
+
+	cw.Flush()
+	return counts, cw.Error()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/corbaltcode/usps/ziptocounty"
+)
+
+func main() {
+	tarName := flag.String("tar", "", "Name of the tar file")
+	deliverableOnly := flag.Bool("deliverable-only", false, "Exclude non-deliverable (ND) ZIP+4 segments")
+	recordTypes := flag.String("record-types", "", "Comma-separated ZIP+4 record type codes to include (default all)")
+	flag.Parse()
+
+	if *tarName == "" {
+		fmt.Fprintf(os.Stderr, "Error: Missing tar file name\nUsage: %s -tar <tar_file_name> [-deliverable-only] [-record-types S,H,...]\n", os.Args[0])
+		os.Exit(1)
+	}
+
+	opts := ziptocounty.CrosswalkOptions{DeliverableOnly: *deliverableOnly}
+	if *recordTypes != "" {
+		opts.RecordTypes = strings.Split(*recordTypes, ",")
+	}
+
+	log.Printf("Building ZIP-to-county crosswalk from %v...\n", *tarName)
+
+	entries, err := ziptocounty.BuildUSPSCrosswalk(*tarName, mustGetenv("ZIP_PASSWORD"), opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to build crosswalk: %v\n", err)
+		os.Exit(1)
+	}
+
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	headers := []string{"ZIP", "COUNTY", "RES_RATIO", "BUS_RATIO", "OTH_RATIO", "TOT_RATIO", "ADDON_COUNT", "PRIMARY"}
+	if err := writer.Write(headers); err != nil {
+		log.Fatalf("Error writing headers to CSV: %v", err)
+	}
+
+	for _, e := range entries {
+		record := []string{
+			e.Zipcode,
+			e.CountyFips,
+			formatRatio(e.ResidentialRatio),
+			formatRatio(e.BusinessRatio),
+			formatRatio(e.OtherRatio),
+			formatRatio(e.TotalRatio),
+			strconv.Itoa(e.AddOnCount),
+			strconv.FormatBool(e.Primary),
+		}
+		if err := writer.Write(record); err != nil {
+			log.Fatalf("Error writing to CSV: %v", err)
+		}
+	}
+}
+
+func formatRatio(r float64) string {
+	return strconv.FormatFloat(r, 'f', 6, 64)
+}
+
+func mustGetenv(key string) string {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		panic(fmt.Sprintf("missing env var: %v", key))
+	}
+	return v
+}
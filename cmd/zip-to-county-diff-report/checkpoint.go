@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"slices"
+
+	"github.com/corbaltcode/usps/smarty"
+	"github.com/corbaltcode/usps/ziptocounty"
+)
+
+// checkpointEntry records the outcome of one batch. Batches that failed
+// carry an error and the lookups made before the failure; lookups of single
+// ZIPs can fail too, such as when Smarty returns an error status.
+type checkpointEntry struct {
+	Batch  int                        `json:"batch"`
+	Zips   []string                   `json:"zips"`
+	Base   []ziptocounty.CountyLookup `json:"base,omitempty"`
+	Source []ziptocounty.CountyLookup `json:"source,omitempty"`
+	Error  string                     `json:"error,omitempty"`
+}
+
+// complete reports whether the batch succeeded with no ZIP lookups that
+// might succeed if retried. Incomplete batches are queried again on resume.
+func (e checkpointEntry) complete() bool {
+	if e.Error != "" {
+		return false
+	}
+	for _, lookups := range [][]ziptocounty.CountyLookup{e.Base, e.Source} {
+		for _, l := range lookups {
+			// An invalid ZIP stays invalid.
+			if l.Error != "" && l.Status != smarty.StatusInvalidZipcode {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// checkpoint is an append-only JSON Lines file of batch outcomes. When a
+// batch appears more than once, the last entry wins.
+type checkpoint struct {
+	f         *os.File
+	enc       *json.Encoder
+	completed map[int]checkpointEntry
+}
+
+// openCheckpoint creates the checkpoint file, or if resume is set, reads the
+// batches already completed and appends to it.
+func openCheckpoint(fileName string, resume bool) (*checkpoint, error) {
+	c := &checkpoint{completed: make(map[int]checkpointEntry)}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if resume {
+		if err := c.read(fileName); err != nil {
+			return nil, err
+		}
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+
+	f, err := os.OpenFile(fileName, flags, 0644)
+	if err != nil {
+		return nil, err
+	}
+	c.f = f
+	c.enc = json.NewEncoder(f)
+
+	return c, nil
+}
+
+func (c *checkpoint) read(fileName string) error {
+	f, err := os.Open(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var offset int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			// A run that crashed mid-write leaves a partial last line, which
+			// must go before more entries are appended.
+			if len(line) > 0 {
+				log.Printf("Discarding incomplete last line of checkpoint %v", fileName)
+				return os.Truncate(fileName, offset)
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+		offset += int64(len(line))
+
+		var e checkpointEntry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return err
+		}
+
+		if e.complete() {
+			c.completed[e.Batch] = e
+		} else {
+			delete(c.completed, e.Batch)
+		}
+	}
+
+	return nil
+}
+
+// completedBatch returns the entry for a batch that completed with the same
+// ZIPs in an earlier run.
+func (c *checkpoint) completedBatch(batch int, zips []string) (checkpointEntry, bool) {
+	e, ok := c.completed[batch]
+	if !ok || !slices.Equal(e.Zips, zips) || len(e.Base) != len(zips) || len(e.Source) != len(zips) {
+		return checkpointEntry{}, false
+	}
+	return e, true
+}
+
+func (c *checkpoint) record(e checkpointEntry) error {
+	if err := c.enc.Encode(e); err != nil {
+		return err
+	}
+	return c.f.Sync()
+}
+
+func (c *checkpoint) Close() error {
+	return c.f.Close()
+}
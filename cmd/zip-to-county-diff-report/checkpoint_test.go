@@ -0,0 +1,133 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/corbaltcode/usps/ziptocounty"
+)
+
+func lookups(zips []string, errs ...string) []ziptocounty.CountyLookup {
+	var ls []ziptocounty.CountyLookup
+	for i, zip := range zips {
+		l := ziptocounty.CountyLookup{Zipcode: zip, Found: true, Counties: []ziptocounty.County{{Fips: "51013"}}}
+		if i < len(errs) && errs[i] != "" {
+			l = ziptocounty.CountyLookup{Zipcode: zip, Error: errs[i]}
+		}
+		ls = append(ls, l)
+	}
+	return ls
+}
+
+func TestCheckpointResume(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+	batches := [][]string{{"22201", "22202"}, {"22203"}, {"22204"}, {"22205"}, {"00000"}}
+
+	cp, err := openCheckpoint(fileName, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := []checkpointEntry{
+		{Batch: 0, Zips: batches[0], Base: lookups(batches[0]), Source: lookups(batches[0])},
+		{Batch: 1, Zips: batches[1], Error: "smarty lookup failed: rate limited"},
+		{Batch: 2, Zips: batches[2], Base: lookups(batches[2]), Source: lookups(batches[2], "Status response: internal_error")},
+		// Batch 3 failed, then succeeded in a later run.
+		{Batch: 3, Zips: batches[3], Error: "smarty lookup failed: timeout"},
+		{Batch: 3, Zips: batches[3], Base: lookups(batches[3]), Source: lookups(batches[3])},
+		// Retrying an invalid ZIP won't help.
+		{Batch: 4, Zips: batches[4], Base: lookups(batches[4]), Source: []ziptocounty.CountyLookup{{Zipcode: "00000", Error: "Status response: invalid_zipcode", Status: "invalid_zipcode"}}},
+	}
+	for _, e := range entries {
+		if err := cp.record(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cp.Close()
+
+	cp, err = openCheckpoint(fileName, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cp.Close()
+
+	for batch, want := range []bool{true, false, false, true, true} {
+		if _, got := cp.completedBatch(batch, batches[batch]); got != want {
+			t.Errorf("batch %v: completed = %v, want %v", batch, got, want)
+		}
+	}
+	if _, ok := cp.completedBatch(0, []string{"22201"}); ok {
+		t.Error("batch 0 completed with different ZIPs")
+	}
+	if _, ok := cp.completedBatch(5, []string{"22206"}); ok {
+		t.Error("batch 5 never ran but completed")
+	}
+}
+
+func TestCheckpointTruncatesPartialLine(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+	zips := []string{"22201"}
+
+	cp, err := openCheckpoint(fileName, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cp.record(checkpointEntry{Batch: 0, Zips: zips, Base: lookups(zips), Source: lookups(zips)}); err != nil {
+		t.Fatal(err)
+	}
+	cp.Close()
+
+	// A crash mid-write leaves half an entry with no newline.
+	f, err := os.OpenFile(fileName, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"batch":1,"zips":["222`)
+	f.Close()
+
+	cp, err = openCheckpoint(fileName, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cp.completedBatch(0, zips); !ok {
+		t.Error("batch 0 not completed")
+	}
+	if err := cp.record(checkpointEntry{Batch: 1, Zips: []string{"22202"}, Error: "failed"}); err != nil {
+		t.Fatal(err)
+	}
+	cp.Close()
+
+	b, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], `{"batch":1,"zips":["22202"]`) {
+		t.Errorf("unexpected checkpoint after resume:\n%s", b)
+	}
+}
+
+func TestCheckpointWithoutResumeTruncates(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+	zips := []string{"22201"}
+
+	cp, err := openCheckpoint(fileName, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cp.record(checkpointEntry{Batch: 0, Zips: zips, Base: lookups(zips), Source: lookups(zips)})
+	cp.Close()
+
+	cp, err = openCheckpoint(fileName, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cp.Close()
+	if _, ok := cp.completedBatch(0, zips); ok {
+		t.Error("batch 0 completed without -resume")
+	}
+	if fi, err := os.Stat(fileName); err != nil || fi.Size() != 0 {
+		t.Errorf("checkpoint not truncated: %v, %v", fi, err)
+	}
+}
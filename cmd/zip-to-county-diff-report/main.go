@@ -1,102 +1,232 @@
 package main
 
 import (
-	"encoding/csv"
 	"flag"
 	"fmt"
-	"io"
 	"log"
 	"os"
-	"strconv"
-	"strings"
-	"time"
+	"sort"
 
-	"github.com/corbaltcode/usps/internal/smarty"
-	"github.com/corbaltcode/usps/internal/ziptocounty"
+	"github.com/corbaltcode/usps/census"
+	"github.com/corbaltcode/usps/smarty"
+	"github.com/corbaltcode/usps/ziptocounty"
 )
 
+const providerUsage = "usps (tar), sqlite (seed-db database), hud (crosswalk CSV or XLSX), census (ZCTA relationship file) or smarty"
+
 func main() {
-	tarName := flag.String("tar", "", "Name of the tar file")
+	tarName := flag.String("tar", "", "Name of the tar file (shorthand for -base usps -base-file <tar>)")
+	base := flag.String("base", "usps", "Base provider: "+providerUsage)
+	baseFile := flag.String("base-file", "", "File for the base provider")
+	source := flag.String("source", "smarty", "Comparison provider: "+providerUsage)
+	sourceFile := flag.String("source-file", "", "File for the comparison provider")
+	smartyCache := flag.String("smarty-cache", "", "SQLite database caching Smarty responses")
+	smartyCacheTTL := flag.Duration("smarty-cache-ttl", 0, "How long cached Smarty responses stay fresh (default forever)")
+	smartyCacheErrorTTL := flag.Duration("smarty-cache-error-ttl", 0, "How long cached failed Smarty lookups stay fresh (default not cached)")
+	smartyRefresh := flag.Bool("smarty-refresh", false, "Re-query every ZIP from Smarty, updating the cache")
+	smartyRPS := flag.Float64("smarty-rps", 0.5, "Maximum Smarty requests per second")
+	smartyConcurrency := flag.Int("smarty-concurrency", 1, "Number of concurrent Smarty requests")
+	gazetteer := flag.String("gazetteer", "", "Census ZCTA Gazetteer file; adds ZIP centroid columns")
+	outName := flag.String("out", "", "Output file (default stdout)")
+	format := flag.String("format", "csv", "Output format: csv, jsonl, html or summary")
+	worst := flag.Int("worst", 25, "Number of worst ZIPs to list in summaries")
+	checkpointName := flag.String("checkpoint", "", "Checkpoint file recording completed batches and provider responses")
+	resume := flag.Bool("resume", false, "Resume from the checkpoint, re-querying only batches that failed, had ZIP lookup errors other than invalid ZIPs or never ran")
+	batchSize := flag.Int("batch-size", 0, "ZIPs per checkpointed batch (default 100 per concurrent Smarty request)")
 	flag.Parse()
 
-	if *tarName == "" {
-		fmt.Fprintf(os.Stderr, "Error: Missing tar file name\nUsage: %s -tar <tar_file_name> \n", os.Args[0])
+	if *tarName != "" {
+		*base = "usps"
+		*baseFile = *tarName
+	}
+
+	if (*base != "smarty" && *baseFile == "") || (*source != "smarty" && *sourceFile == "") {
+		fmt.Fprintf(os.Stderr, "Error: Missing required parameters\nUsage: %s (-tar <tar_file_name> | -base <provider> -base-file <file>) [-source <provider>] [-source-file <file>] [-gazetteer <file>] [-out <file>] [-format csv|jsonl|html|summary] [-checkpoint <file> [-resume]]\n", os.Args[0])
+		os.Exit(1)
+	}
+	if *format != "csv" && *format != "jsonl" && *format != "html" && *format != "summary" {
+		fmt.Fprintf(os.Stderr, "Error: Unknown format %q\n", *format)
+		os.Exit(1)
+	}
+	if *resume && *checkpointName == "" {
+		fmt.Fprintf(os.Stderr, "Error: -resume requires -checkpoint\n")
 		os.Exit(1)
 	}
 
-	zipPassword := mustGetenv("ZIP_PASSWORD")
-	authId := mustGetenv("AUTH_ID")
-	authToken := mustGetenv("AUTH_TOKEN")
-	client := smarty.NewClient(authId, authToken)
+	providerOpts := ziptocounty.ProviderOptions{
+		Smarty: ziptocounty.SmartyOptions{
+			CacheName: *smartyCache,
+			CacheTTL:  *smartyCacheTTL,
+			ErrorTTL:  *smartyCacheErrorTTL,
+			Refresh:   *smartyRefresh,
+			Pool:      smarty.PoolOptions{RequestsPerSecond: *smartyRPS, Concurrency: *smartyConcurrency},
+		},
+	}
+	// Only the providers in use need credentials.
+	for _, kind := range []string{*base, *source} {
+		switch kind {
+		case "usps":
+			providerOpts.ZipPassword = mustGetenv("ZIP_PASSWORD")
+		case "smarty":
+			providerOpts.Smarty.AuthID = mustGetenv("AUTH_ID")
+			providerOpts.Smarty.AuthToken = mustGetenv("AUTH_TOKEN")
+		}
+	}
 
-	log.Printf("Extracting USPS zip data from %v and mapping zips to corresponding USPS counties...\n", *tarName)
+	baseProvider, err := ziptocounty.OpenProvider(*base, *baseFile, providerOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load base provider: %v\n", err)
+		os.Exit(1)
+	}
+	sourceProvider, err := ziptocounty.OpenProvider(*source, *sourceFile, providerOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load comparison provider: %v\n", err)
+		os.Exit(1)
+	}
 
-	zipToCounty, err := ziptocounty.CollectUSPSZip4Details(*tarName, zipPassword)
+	lister, ok := baseProvider.(ziptocounty.ZipLister)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Base provider %v cannot list ZIPs\n", baseProvider.Name())
+		os.Exit(1)
+	}
+	zips, err := lister.Zipcodes()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to collect details: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Failed to list ZIPs: %v\n", err)
 		os.Exit(1)
 	}
+	// Batches must hold the same ZIPs from run to run for resuming to work.
+	sort.Strings(zips)
 
-	// Get the list of zip codes for batch requests.
-	zips := getZipCodes(zipToCounty)
+	var centroids map[string]census.ZCTAGazetteer
+	if *gazetteer != "" {
+		log.Printf("Reading ZCTA centroids from %v...\n", *gazetteer)
+		centroids, err = ziptocounty.CollectZCTACentroids(*gazetteer)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read gazetteer: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
-	writer := setupCSVWriter(os.Stdout)
-	defer writer.Flush()
+	var cp *checkpoint
+	if *checkpointName != "" {
+		cp, err = openCheckpoint(*checkpointName, *resume)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open checkpoint: %v\n", err)
+			os.Exit(1)
+		}
+		defer cp.Close()
+	}
 
-	const batchSize = 100
-	const rateLimitPause = 2 * time.Second
+	output := os.Stdout
+	if *outName != "" {
+		output, err = os.Create(*outName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create output file: %v\n", err)
+			os.Exit(1)
+		}
+		defer output.Close()
+	}
 
-	for i := 0; i < len(zips); i += batchSize {
-		end := min(i+batchSize, len(zips))
-		batch := zips[i:end]
-		responseBody, err := client.QueryBatch(batch)
+	report := &ziptocounty.Report{
+		BaseName:  baseProvider.Name(),
+		OtherName: sourceProvider.Name(),
+		Centroids: centroids,
+	}
 
-		if err != nil {
-			log.Printf("Error querying Smarty API: %v", err)
-			continue
+	if *batchSize <= 0 {
+		*batchSize = smarty.MaxBatchSize * max(1, *smartyConcurrency)
+	}
+
+	failed := 0
+	for batch, i := 0, 0; i < len(zips); batch, i = batch+1, i+*batchSize {
+		end := min(i+*batchSize, len(zips))
+		batchZips := zips[i:end]
+
+		var entry checkpointEntry
+		resumed := false
+		if cp != nil {
+			entry, resumed = cp.completedBatch(batch, batchZips)
 		}
 
-		if len(responseBody) != len(batch) {
-			log.Printf("Mismatched response count: received %d responses for %d queried ZIPs", len(responseBody), len(batch))
-			continue
+		if !resumed {
+			entry = lookupBatch(batch, batchZips, baseProvider, sourceProvider)
+			if cp != nil {
+				if err := cp.record(entry); err != nil {
+					log.Fatalf("Error writing checkpoint: %v", err)
+				}
+			}
 		}
 
-		for j, response := range responseBody {
-			zip := batch[j]
-			diff := ziptocounty.GenerateSmartyUSPSDiff(zip, response, zipToCounty)
+		if entry.Error != "" {
+			log.Printf("Error comparing providers for batch %v: %v", batch, entry.Error)
+		}
+		report.Diffs = append(report.Diffs, batchDiffs(entry)...)
+		if !entry.complete() {
+			failed++
+		}
 
-			record := []string{
-				diff.Zipcode,
-				strings.Join(diff.USPSFips, ","),
-				strings.Join(diff.SmartyFips, ","),
-				strconv.Itoa(diff.MismatchCount),
-				diff.ErrorMessage,
-			}
-			if err := writer.Write(record); err != nil {
-				log.Printf("Error writing to CSV: %v", err)
-			}
+		if !resumed {
+			log.Printf("%v zips processed.", end)
 		}
+	}
+
+	if failed > 0 {
+		log.Printf("%v batches failed or had ZIP lookup errors other than invalid ZIPs; rerun with -checkpoint and -resume to retry them.", failed)
+	}
 
-		log.Printf("%v zips processed.", i+batchSize)
-		time.Sleep(rateLimitPause)
+	switch *format {
+	case "csv":
+		err = report.WriteCSV(output)
+	case "jsonl":
+		err = report.WriteJSONLines(output)
+	case "html":
+		err = report.WriteHTML(output, *worst)
+	case "summary":
+		err = report.WriteSummary(output, *worst)
+	}
+	if err != nil {
+		log.Fatalf("Error writing report: %v", err)
 	}
 }
 
-func getZipCodes(zipToCounty map[string][]string) []string {
-	zipCodes := make([]string, 0, len(zipToCounty))
-	for zip := range zipToCounty {
-		zipCodes = append(zipCodes, zip)
+func lookupBatch(batch int, zips []string, base, source ziptocounty.CountyProvider) checkpointEntry {
+	entry := checkpointEntry{Batch: batch, Zips: zips}
+
+	baseLookups, err := base.LookupCounties(zips)
+	if err != nil {
+		entry.Error = fmt.Sprintf("%v lookup failed: %v", base.Name(), err)
+		return entry
+	}
+	entry.Base = baseLookups
+
+	sourceLookups, err := source.LookupCounties(zips)
+	if err != nil {
+		entry.Error = fmt.Sprintf("%v lookup failed: %v", source.Name(), err)
+		return entry
 	}
-	return zipCodes
+	entry.Source = sourceLookups
+	return entry
 }
 
-func setupCSVWriter(output io.Writer) *csv.Writer {
-	writer := csv.NewWriter(output)
-	headers := []string{"Zipcode", "USPS Fips", "Smarty Fips", "Mismatch Count", "Error"}
-	if err := writer.Write(headers); err != nil {
-		log.Fatalf("Error writing headers to CSV: %v", err)
+// batchDiffs diffs the lookups of a batch. The ZIPs of a batch that failed
+// are source errors, with the error on the provider that failed.
+func batchDiffs(entry checkpointEntry) []ziptocounty.ZIPCountyDiff {
+	diffs := make([]ziptocounty.ZIPCountyDiff, len(entry.Zips))
+	for j, zip := range entry.Zips {
+		base := ziptocounty.CountyLookup{Zipcode: zip}
+		source := ziptocounty.CountyLookup{Zipcode: zip}
+		switch {
+		case len(entry.Base) != len(entry.Zips):
+			base.Error = entry.Error
+		case len(entry.Source) != len(entry.Zips):
+			base = entry.Base[j]
+			source.Error = entry.Error
+		default:
+			base, source = entry.Base[j], entry.Source[j]
+		}
+		diffs[j] = ziptocounty.GenerateDiff(base, source)
 	}
-	return writer
+	return diffs
 }
 
 func mustGetenv(key string) string {
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/corbaltcode/usps/ziptocounty"
+)
+
+func TestBatchDiffsOfFailedBatch(t *testing.T) {
+	zips := []string{"22201", "22202"}
+
+	tests := []struct {
+		name                    string
+		entry                   checkpointEntry
+		wantBaseErr, wantSrcErr bool
+		wantBaseFips            int
+	}{
+		{"base failed", checkpointEntry{Zips: zips, Error: "usps lookup failed"}, true, false, 0},
+		{"source failed", checkpointEntry{Zips: zips, Base: lookups(zips), Error: "smarty lookup failed"}, false, true, 1},
+	}
+	for _, tt := range tests {
+		diffs := batchDiffs(tt.entry)
+		if len(diffs) != len(zips) {
+			t.Fatalf("%v: got %v diffs, want %v", tt.name, len(diffs), len(zips))
+		}
+		for i, d := range diffs {
+			if d.Zipcode != zips[i] || d.Category != ziptocounty.CategorySourceError {
+				t.Errorf("%v: diff %v = %+v, want a source error for %v", tt.name, i, d, zips[i])
+			}
+			if (d.BaseError != "") != tt.wantBaseErr || (d.OtherError != "") != tt.wantSrcErr {
+				t.Errorf("%v: diff %v has base error %q and source error %q", tt.name, i, d.BaseError, d.OtherError)
+			}
+			if len(d.BaseFips) != tt.wantBaseFips {
+				t.Errorf("%v: diff %v has base FIPS %v", tt.name, i, d.BaseFips)
+			}
+		}
+	}
+}
@@ -5,56 +5,88 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/corbaltcode/usps/internal/smarty"
-	"github.com/corbaltcode/usps/internal/ziptocounty"
+	"github.com/corbaltcode/usps/smarty"
+	"github.com/corbaltcode/usps/ziptocounty"
 )
 
+const providerUsage = "usps (tar), sqlite (seed-db database), hud (crosswalk CSV or XLSX), census (ZCTA relationship file) or smarty"
+
 func main() {
-	tarName := flag.String("tar", "", "Name of the tar file")
+	tarName := flag.String("tar", "", "Name of the tar file (shorthand for -base usps -base-file <tar>)")
 	zipCode := flag.String("zip", "", "Single zip to look up")
+	base := flag.String("base", "usps", "Base provider: "+providerUsage)
+	baseFile := flag.String("base-file", "", "File for the base provider")
+	source := flag.String("source", "smarty", "Comparison provider: "+providerUsage)
+	sourceFile := flag.String("source-file", "", "File for the comparison provider")
+	smartyCache := flag.String("smarty-cache", "", "SQLite database caching Smarty responses")
+	smartyCacheTTL := flag.Duration("smarty-cache-ttl", 0, "How long cached Smarty responses stay fresh (default forever)")
+	smartyCacheErrorTTL := flag.Duration("smarty-cache-error-ttl", 0, "How long cached failed Smarty lookups stay fresh (default not cached)")
+	smartyRefresh := flag.Bool("smarty-refresh", false, "Re-query every ZIP from Smarty, updating the cache")
+	smartyRPS := flag.Float64("smarty-rps", 0.5, "Maximum Smarty requests per second")
+	smartyConcurrency := flag.Int("smarty-concurrency", 1, "Number of concurrent Smarty requests")
 	flag.Parse()
 
-	if *tarName == "" || *zipCode == "" {
-		fmt.Fprintf(os.Stderr, "Error: Missing required parameters\nUsage: %s -tar <tar_file_name> -zip <single_zip_code>\n", os.Args[0])
-		os.Exit(1)
+	if *tarName != "" {
+		*base = "usps"
+		*baseFile = *tarName
 	}
 
-	zipPassword := mustGetenv("ZIP_PASSWORD")
-	authId := mustGetenv("AUTH_ID")
-	authToken := mustGetenv("AUTH_TOKEN")
-	client := smarty.NewClient(authId, authToken)
+	if *zipCode == "" || (*base != "smarty" && *baseFile == "") || (*source != "smarty" && *sourceFile == "") {
+		fmt.Fprintf(os.Stderr, "Error: Missing required parameters\nUsage: %s (-tar <tar_file_name> | -base <provider> -base-file <file>) -zip <single_zip_code> [-source <provider>] [-source-file <file>]\n", os.Args[0])
+		os.Exit(1)
+	}
 
-	fmt.Printf("Extracting USPS zip data from %s and mapping %v to corresponding USPS counties...\n", *tarName, *zipCode)
+	providerOpts := ziptocounty.ProviderOptions{
+		Smarty: ziptocounty.SmartyOptions{
+			CacheName: *smartyCache,
+			CacheTTL:  *smartyCacheTTL,
+			ErrorTTL:  *smartyCacheErrorTTL,
+			Refresh:   *smartyRefresh,
+			Pool:      smarty.PoolOptions{RequestsPerSecond: *smartyRPS, Concurrency: *smartyConcurrency},
+		},
+	}
+	// Only the providers in use need credentials.
+	for _, kind := range []string{*base, *source} {
+		switch kind {
+		case "usps":
+			providerOpts.ZipPassword = mustGetenv("ZIP_PASSWORD")
+		case "smarty":
+			providerOpts.Smarty.AuthID = mustGetenv("AUTH_ID")
+			providerOpts.Smarty.AuthToken = mustGetenv("AUTH_TOKEN")
+		}
+	}
 
-	zipToCounty, err := ziptocounty.CollectUSPSZip4Details(*tarName, zipPassword)
+	baseProvider, err := ziptocounty.OpenProvider(*base, *baseFile, providerOpts)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to collect details: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Failed to load base provider: %v\n", err)
 		os.Exit(1)
 	}
-
-	counties, found := zipToCounty[*zipCode]
-	if !found || len(counties) == 0 {
-		fmt.Fprintf(os.Stderr, "Error: No counties found for ZIP Code: %s\n", *zipCode)
+	sourceProvider, err := ziptocounty.OpenProvider(*source, *sourceFile, providerOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load comparison provider: %v\n", err)
 		os.Exit(1)
 	}
 
-	zipResponse, err := client.QueryBatch([]string{*zipCode})
+	diffs, err := ziptocounty.CompareProviders(baseProvider, sourceProvider, []string{*zipCode})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error querying Smarty API: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error comparing providers: %v\n", err)
 		os.Exit(1)
 	}
 
-	for _, response := range zipResponse {
-		diff := ziptocounty.GenerateSmartyUSPSDiff(*zipCode, response, zipToCounty)
-
-		fmt.Printf("ZIP Code Differences for %s:\n", *zipCode)
-		fmt.Printf("  - USPS FIPS Codes:   %v\n", diff.USPSFips)
-		fmt.Printf("  - Smarty FIPS Codes: %v\n", diff.SmartyFips)
-		fmt.Printf("  - Mismatch Count:    %d\n", diff.MismatchCount)
-		if diff.ErrorMessage != "" {
-			fmt.Printf("  - Error:             %s\n", diff.ErrorMessage)
-		} else {
-			fmt.Printf("  - Status:            No errors detected.\n")
+	for _, diff := range diffs {
+		fmt.Printf("ZIP Code Differences for %s:\n", diff.Zipcode)
+		fmt.Printf("  - Category:             %s\n", diff.Category)
+		fmt.Printf("  - Base FIPS Codes:      %v (%s)\n", diff.BaseFips, baseProvider.Name())
+		fmt.Printf("  - Source FIPS Codes:    %v (%s)\n", diff.OtherFips, sourceProvider.Name())
+		fmt.Printf("  - Only In Base:         %v\n", diff.OnlyInBase)
+		fmt.Printf("  - Only In Source:       %v\n", diff.OnlyInOther)
+		fmt.Printf("  - In Both:              %v\n", diff.InBoth)
+		fmt.Printf("  - Mismatch Count:       %d\n", diff.MismatchCount)
+		if diff.BaseError != "" {
+			fmt.Printf("  - Base Error:           %s\n", diff.BaseError)
+		}
+		if diff.OtherError != "" {
+			fmt.Printf("  - Source Error:         %s\n", diff.OtherError)
 		}
 		fmt.Println("--------------------------------------------------")
 	}
@@ -0,0 +1,320 @@
+// Package export writes ZIP+4 and City State records to Parquet, CSV and
+// NDJSON files for loading into a data warehouse.
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/corbaltcode/usps/citystate"
+	"github.com/corbaltcode/usps/zip4"
+	"github.com/parquet-go/parquet-go"
+)
+
+type Format string
+
+const (
+	Parquet Format = "parquet"
+	CSV     Format = "csv"
+	NDJSON  Format = "ndjson"
+)
+
+// DefaultRowGroupSize is the default number of rows in a Parquet row group.
+// Each open file buffers up to a row group in memory.
+const DefaultRowGroupSize = 250000
+
+// parquetWriteBatch is how many records are passed to the Parquet writer at
+// once.
+const parquetWriteBatch = 1024
+
+type Options struct {
+	Format Format
+	// Dir is the directory files are written to.
+	Dir string
+	// PartitionByState writes a file per state, in Hive-style directories:
+	// Dir/zip4/state=VA/zip4.parquet. Otherwise a single file is written:
+	// Dir/zip4.parquet.
+	PartitionByState bool
+	// RowGroupSize is the number of rows in a Parquet row group.
+	RowGroupSize int64
+}
+
+// Zip4Record is an exported zip4.Zip4Detail. Add-ons are integers; they're
+// null for non-deliverable (ND) ranges.
+type Zip4Record struct {
+	ZipCode           string `parquet:"zip_code,dict" json:"zip_code"`
+	UpdateKey         string `parquet:"update_key" json:"update_key"`
+	ActionCode        string `parquet:"action_code,dict" json:"action_code"`
+	RecordTypeCode    string `parquet:"record_type_code,dict" json:"record_type_code"`
+	StateAbbreviation string `parquet:"state_abbreviation,dict" json:"state_abbreviation"`
+	CountyNumber      string `parquet:"county_number,dict" json:"county_number"`
+	Sector            string `parquet:"sector,dict" json:"sector"`
+	Plus4Low          *int32 `parquet:"plus4_low,optional" json:"plus4_low"`
+	Plus4High         *int32 `parquet:"plus4_high,optional" json:"plus4_high"`
+	Deliverable       bool   `parquet:"deliverable" json:"deliverable"`
+}
+
+func NewZip4Record(d zip4.Zip4Detail) Zip4Record {
+	return Zip4Record{
+		ZipCode:           d.ZipCode,
+		UpdateKey:         d.UpdateKey,
+		ActionCode:        d.ActionCode,
+		RecordTypeCode:    d.RecordTypeCode,
+		StateAbbreviation: d.StateAbbreviation,
+		CountyNumber:      d.CountyNumber,
+		Sector:            d.Plus4LowNumber.Sector(),
+		Plus4Low:          zip4NumberPtr(d.Plus4LowNumber),
+		Plus4High:         zip4NumberPtr(d.Plus4HighNumber),
+		Deliverable:       d.Plus4LowNumber.IsDeliverable() && d.Plus4HighNumber.IsDeliverable(),
+	}
+}
+
+func zip4NumberPtr(n zip4.Zip4Number) *int32 {
+	i, err := n.Int()
+	if err != nil {
+		return nil
+	}
+	i32 := int32(i)
+	return &i32
+}
+
+// CityStateRecord is an exported citystate.CityStateDetail.
+type CityStateRecord struct {
+	ZipCode                        string `parquet:"zip_code,dict" json:"zip_code"`
+	CityStateKey                   string `parquet:"city_state_key" json:"city_state_key"`
+	ZipClassificationCode          string `parquet:"zip_classification_code,dict" json:"zip_classification_code"`
+	CityStateName                  string `parquet:"city_state_name,dict" json:"city_state_name"`
+	CityStateNameAbbreviation      string `parquet:"city_state_name_abbreviation" json:"city_state_name_abbreviation"`
+	CityStateNameFacilityCode      string `parquet:"city_state_name_facility_code,dict" json:"city_state_name_facility_code"`
+	CityStateMailingNameIndicator  string `parquet:"city_state_mailing_name_indicator,dict" json:"city_state_mailing_name_indicator"`
+	PreferredLastLineCityStateKey  string `parquet:"preferred_last_line_city_state_key,dict" json:"preferred_last_line_city_state_key"`
+	PreferredLastLineCityStateName string `parquet:"preferred_last_line_city_state_name,dict" json:"preferred_last_line_city_state_name"`
+	CityDeliveryIndicator          string `parquet:"city_delivery_indicator,dict" json:"city_delivery_indicator"`
+	CarrierRouteRateSortation      string `parquet:"carrier_route_rate_sortation,dict" json:"carrier_route_rate_sortation"`
+	UniqueZipNameIndicator         string `parquet:"unique_zip_name_indicator,dict" json:"unique_zip_name_indicator"`
+	FinanceNumber                  string `parquet:"finance_number,dict" json:"finance_number"`
+	StateAbbreviation              string `parquet:"state_abbreviation,dict" json:"state_abbreviation"`
+	CountyNumber                   string `parquet:"county_number,dict" json:"county_number"`
+	CountyName                     string `parquet:"county_name,dict" json:"county_name"`
+}
+
+func NewCityStateRecord(d citystate.CityStateDetail) CityStateRecord {
+	return CityStateRecord{
+		ZipCode:                        d.ZipCode,
+		CityStateKey:                   d.CityStateKey,
+		ZipClassificationCode:          d.ZipClassificationCode,
+		CityStateName:                  d.CityStateName,
+		CityStateNameAbbreviation:      d.CityStateNameAbbreviation,
+		CityStateNameFacilityCode:      d.CityStateNameFacilityCode,
+		CityStateMailingNameIndicator:  d.CityStateMailingNameIndicator,
+		PreferredLastLineCityStateKey:  d.PreferredLastLineCityStateKey,
+		PreferredLastLineCityStateName: d.PreferredLastLineCityStateName,
+		CityDeliveryIndicator:          d.CityDeliveryIndicator,
+		CarrierRouteRateSortation:      d.CarrierRouteRateSortation,
+		UniqueZipNameIndicator:         d.UniqueZipNameIndicator,
+		FinanceNumber:                  d.FinanceNumber,
+		StateAbbreviation:              d.StateAbbreviation,
+		CountyNumber:                   d.CountyNumber,
+		CountyName:                     d.CountyName,
+	}
+}
+
+// ExportZip4 writes the records read yields as it reads them and returns
+// the names of the files written and the number of records.
+func ExportZip4(read func(yield func(zip4.Zip4Detail)) error, opts Options) ([]string, int, error) {
+	files := newFiles[Zip4Record]("zip4", opts)
+	err := read(func(d zip4.Zip4Detail) {
+		files.write(d.StateAbbreviation, NewZip4Record(d))
+	})
+	return files.close(err)
+}
+
+// ExportCityState writes the records read yields as it reads them and
+// returns the names of the files written and the number of records.
+func ExportCityState(read func(yield func(citystate.CityStateDetail)) error, opts Options) ([]string, int, error) {
+	files := newFiles[CityStateRecord]("citystate", opts)
+	err := read(func(d citystate.CityStateDetail) {
+		files.write(d.StateAbbreviation, NewCityStateRecord(d))
+	})
+	return files.close(err)
+}
+
+// files holds the open files of a dataset, one per state when partitioned.
+// Since readers yield records without a way to stop them, the first error
+// is kept and later records are dropped.
+type files[T any] struct {
+	dataset string
+	opts    Options
+	writers map[string]*fileWriter[T]
+	records int
+	err     error
+}
+
+func newFiles[T any](dataset string, opts Options) *files[T] {
+	return &files[T]{dataset: dataset, opts: opts, writers: make(map[string]*fileWriter[T])}
+}
+
+func (f *files[T]) write(state string, record T) {
+	if f.err != nil {
+		return
+	}
+
+	name := filepath.Join(f.opts.Dir, f.dataset+"."+string(f.opts.Format))
+	if f.opts.PartitionByState {
+		name = filepath.Join(f.opts.Dir, f.dataset, "state="+state, f.dataset+"."+string(f.opts.Format))
+	}
+
+	w, ok := f.writers[name]
+	if !ok {
+		w, f.err = newFileWriter[T](name, f.opts)
+		if f.err != nil {
+			return
+		}
+		f.writers[name] = w
+	}
+
+	f.err = w.write(record)
+	f.records++
+}
+
+func (f *files[T]) close(err error) ([]string, int, error) {
+	if err == nil {
+		err = f.err
+	}
+
+	var names []string
+	for name, w := range f.writers {
+		if closeErr := w.close(); err == nil {
+			err = closeErr
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if err != nil {
+		return nil, 0, err
+	}
+	return names, f.records, nil
+}
+
+// fileWriter writes records of type T to a file in one format.
+type fileWriter[T any] struct {
+	f       *os.File
+	buf     *bufio.Writer
+	format  Format
+	parquet *parquet.GenericWriter[T]
+	batch   []T
+	csv     *csv.Writer
+	json    *json.Encoder
+}
+
+func newFileWriter[T any](name string, opts Options) (*fileWriter[T], error) {
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &fileWriter[T]{f: f, buf: bufio.NewWriter(f), format: opts.Format}
+	switch opts.Format {
+	case Parquet:
+		rowGroupSize := opts.RowGroupSize
+		if rowGroupSize <= 0 {
+			rowGroupSize = DefaultRowGroupSize
+		}
+		w.parquet = parquet.NewGenericWriter[T](w.buf, parquet.MaxRowsPerRowGroup(rowGroupSize), parquet.Compression(&parquet.Snappy))
+	case CSV:
+		w.csv = csv.NewWriter(w.buf)
+		err = w.csv.Write(csvHeader(reflect.TypeOf(*new(T))))
+	case NDJSON:
+		w.json = json.NewEncoder(w.buf)
+	default:
+		err = fmt.Errorf("unknown format: %q", opts.Format)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *fileWriter[T]) write(record T) error {
+	switch w.format {
+	case Parquet:
+		w.batch = append(w.batch, record)
+		if len(w.batch) >= parquetWriteBatch {
+			return w.flushBatch()
+		}
+		return nil
+	case CSV:
+		return w.csv.Write(csvRow(reflect.ValueOf(record)))
+	default:
+		return w.json.Encode(record)
+	}
+}
+
+func (w *fileWriter[T]) flushBatch() error {
+	_, err := w.parquet.Write(w.batch)
+	w.batch = w.batch[:0]
+	return err
+}
+
+func (w *fileWriter[T]) close() error {
+	var err error
+	switch w.format {
+	case Parquet:
+		err = w.flushBatch()
+		if err == nil {
+			err = w.parquet.Close()
+		}
+	case CSV:
+		w.csv.Flush()
+		err = w.csv.Error()
+	}
+	if err == nil {
+		err = w.buf.Flush()
+	}
+	if closeErr := w.f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// csvHeader returns the JSON names of a record type's fields.
+func csvHeader(t reflect.Type) []string {
+	header := make([]string, t.NumField())
+	for i := range header {
+		header[i] = t.Field(i).Tag.Get("json")
+	}
+	return header
+}
+
+// csvRow formats a record's fields. Null fields are empty.
+func csvRow(v reflect.Value) []string {
+	row := make([]string, v.NumField())
+	for i := range row {
+		switch f := v.Field(i).Interface().(type) {
+		case string:
+			row[i] = f
+		case *int32:
+			if f != nil {
+				row[i] = strconv.Itoa(int(*f))
+			}
+		case bool:
+			row[i] = strconv.FormatBool(f)
+		default:
+			panic(fmt.Sprintf("unexpected field type %T", f))
+		}
+	}
+	return row
+}
@@ -0,0 +1,99 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/corbaltcode/usps/zip4"
+	"github.com/parquet-go/parquet-go"
+)
+
+func details() []zip4.Zip4Detail {
+	return []zip4.Zip4Detail{
+		{ZipCode: "22201", UpdateKey: "0000000001", ActionCode: "A", RecordTypeCode: "S", StateAbbreviation: "VA", CountyNumber: "013", Plus4LowNumber: "0001", Plus4HighNumber: "0010"},
+		{ZipCode: "22201", UpdateKey: "0000000002", ActionCode: "A", RecordTypeCode: "S", StateAbbreviation: "VA", CountyNumber: "013", Plus4LowNumber: "00ND", Plus4HighNumber: "00ND"},
+		{ZipCode: "20001", UpdateKey: "0000000003", ActionCode: "A", RecordTypeCode: "F", StateAbbreviation: "DC", CountyNumber: "001", Plus4LowNumber: "0100", Plus4HighNumber: "0100"},
+	}
+}
+
+func read(yield func(zip4.Zip4Detail)) error {
+	for _, d := range details() {
+		yield(d)
+	}
+	return nil
+}
+
+func TestExportParquet(t *testing.T) {
+	dir := t.TempDir()
+	files, n, err := ExportZip4(read, Options{Format: Parquet, Dir: dir, RowGroupSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 || len(files) != 1 {
+		t.Fatalf("got %v records in %v files, want 3 in 1", n, len(files))
+	}
+
+	records, err := parquet.ReadFile[Zip4Record](files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []Zip4Record
+	for _, d := range details() {
+		want = append(want, NewZip4Record(d))
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("got %+v, want %+v", records, want)
+	}
+	if records[1].Plus4Low != nil || records[1].Deliverable {
+		t.Errorf("ND range: got low %v, deliverable %v", records[1].Plus4Low, records[1].Deliverable)
+	}
+
+	f, err := os.Open(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pf, err := parquet.OpenFile(f, info.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(pf.RowGroups()); got != 2 {
+		t.Errorf("got %v row groups, want 2", got)
+	}
+}
+
+func TestExportCSVPartitioned(t *testing.T) {
+	dir := t.TempDir()
+	files, _, err := ExportZip4(read, Options{Format: CSV, Dir: dir, PartitionByState: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(dir, "zip4", "state=DC", "zip4.csv"),
+		filepath.Join(dir, "zip4", "state=VA", "zip4.csv"),
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Fatalf("got files %v, want %v", files, want)
+	}
+
+	bz, err := os.ReadFile(want[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(bz)), "\n")
+	wantLines := []string{
+		"zip_code,update_key,action_code,record_type_code,state_abbreviation,county_number,sector,plus4_low,plus4_high,deliverable",
+		"22201,0000000001,A,S,VA,013,00,1,10,true",
+		"22201,0000000002,A,S,VA,013,00,,,false",
+	}
+	if !reflect.DeepEqual(lines, wantLines) {
+		t.Errorf("got %q, want %q", lines, wantLines)
+	}
+}
@@ -0,0 +1,116 @@
+// Package fips maps USPS state abbreviations and county numbers to FIPS
+// codes.
+package fips
+
+import "fmt"
+
+// stateCodes maps USPS state abbreviations to 2-digit FIPS state codes.
+var stateCodes = map[string]string{
+	"AL": "01",
+	"AK": "02",
+	"AZ": "04",
+	"AR": "05",
+	"CA": "06",
+	"CO": "08",
+	"CT": "09",
+	"DE": "10",
+	"DC": "11",
+	"FL": "12",
+	"GA": "13",
+	"HI": "15",
+	"ID": "16",
+	"IL": "17",
+	"IN": "18",
+	"IA": "19",
+	"KS": "20",
+	"KY": "21",
+	"LA": "22",
+	"ME": "23",
+	"MD": "24",
+	"MA": "25",
+	"MI": "26",
+	"MN": "27",
+	"MS": "28",
+	"MO": "29",
+	"MT": "30",
+	"NE": "31",
+	"NV": "32",
+	"NH": "33",
+	"NJ": "34",
+	"NM": "35",
+	"NY": "36",
+	"NC": "37",
+	"ND": "38",
+	"OH": "39",
+	"OK": "40",
+	"OR": "41",
+	"PA": "42",
+	"RI": "44",
+	"SC": "45",
+	"SD": "46",
+	"TN": "47",
+	"TX": "48",
+	"UT": "49",
+	"VT": "50",
+	"VA": "51",
+	"WA": "53",
+	"WV": "54",
+	"WI": "55",
+	"WY": "56",
+
+	// Territories and freely associated states.
+	"AS": "60",
+	"FM": "64",
+	"GU": "66",
+	"MH": "68",
+	"MP": "69",
+	"PW": "70",
+	"PR": "72",
+	"UM": "74",
+	"VI": "78",
+
+	// Military "states" have no FIPS code. These placeholders lie outside
+	// the range assigned by FIPS 5-2 so they never collide with a real
+	// state or with each other.
+	"AA": "97",
+	"AE": "98",
+	"AP": "99",
+}
+
+var stateAbbreviations = func() map[string]string {
+	m := make(map[string]string, len(stateCodes))
+	for abbr, code := range stateCodes {
+		m[code] = abbr
+	}
+	return m
+}()
+
+// StateCode returns the 2-digit FIPS code for a USPS state abbreviation.
+// The military abbreviations AA, AE and AP have no FIPS code and get the
+// placeholders 97, 98 and 99, which aren't real FIPS codes.
+func StateCode(stateAbbreviation string) (string, bool) {
+	code, ok := stateCodes[stateAbbreviation]
+	return code, ok
+}
+
+// StateAbbreviation returns the USPS state abbreviation for a 2-digit FIPS
+// state code.
+func StateAbbreviation(stateCode string) (string, bool) {
+	abbr, ok := stateAbbreviations[stateCode]
+	return abbr, ok
+}
+
+// CountyCode returns the 5-digit FIPS county code for a USPS state
+// abbreviation and 3-digit county number. Codes for the military
+// abbreviations AA, AE and AP start with the placeholder state codes 97, 98
+// and 99 (see StateCode), so they match no real county.
+func CountyCode(stateAbbreviation string, countyNumber string) (string, error) {
+	stateCode, ok := StateCode(stateAbbreviation)
+	if !ok {
+		return "", fmt.Errorf("unknown state abbreviation: %q", stateAbbreviation)
+	}
+	if len(countyNumber) != 3 {
+		return "", fmt.Errorf("invalid county number: %q", countyNumber)
+	}
+	return stateCode + countyNumber, nil
+}
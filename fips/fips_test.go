@@ -0,0 +1,71 @@
+package fips
+
+import "testing"
+
+func TestStateCodes(t *testing.T) {
+	// 50 states, DC, 9 territories and freely associated states and 3
+	// military abbreviations.
+	if len(stateCodes) != 63 {
+		t.Errorf("got %d state codes, want 63", len(stateCodes))
+	}
+	if len(stateAbbreviations) != len(stateCodes) {
+		t.Errorf("state codes aren't unique: %d abbreviations for %d codes", len(stateAbbreviations), len(stateCodes))
+	}
+
+	for abbr, code := range stateCodes {
+		if len(code) != 2 {
+			t.Errorf("%v: code %q isn't 2 digits", abbr, code)
+		}
+		if got, ok := StateAbbreviation(code); !ok || got != abbr {
+			t.Errorf("StateAbbreviation(%q) = %q, %v, want %q", code, got, ok, abbr)
+		}
+	}
+
+	tests := map[string]string{
+		"AL": "01",
+		"DC": "11",
+		"VA": "51",
+		"WY": "56",
+		"PR": "72",
+		"VI": "78",
+		"AA": "97",
+		"AE": "98",
+		"AP": "99",
+	}
+	for abbr, want := range tests {
+		if got, ok := StateCode(abbr); !ok || got != want {
+			t.Errorf("StateCode(%q) = %q, %v, want %q", abbr, got, ok, want)
+		}
+	}
+
+	if _, ok := StateCode("XX"); ok {
+		t.Error("StateCode(XX) found a code")
+	}
+	if _, ok := StateAbbreviation("03"); ok {
+		t.Error("StateAbbreviation(03) found an abbreviation")
+	}
+}
+
+func TestCountyCode(t *testing.T) {
+	tests := []struct {
+		state, county string
+		want          string
+		wantErr       bool
+	}{
+		{"VA", "013", "51013", false},
+		{"NY", "103", "36103", false},
+		{"PR", "127", "72127", false},
+		{"AE", "000", "98000", false},
+		{"XX", "013", "", true},
+		{"va", "013", "", true},
+		{"VA", "13", "", true},
+		{"VA", "0013", "", true},
+	}
+
+	for _, test := range tests {
+		got, err := CountyCode(test.state, test.county)
+		if (err != nil) != test.wantErr || got != test.want {
+			t.Errorf("CountyCode(%q, %q) = %q, %v, want %q", test.state, test.county, got, err, test.want)
+		}
+	}
+}
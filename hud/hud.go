@@ -0,0 +1,171 @@
+// Package hud reads the HUD USPS ZIP-COUNTY crosswalk file.
+package hud
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type ZipCounty struct {
+	ZipCode          string
+	CountyFIPS       string
+	PreferredCity    string
+	PreferredState   string
+	ResidentialRatio float64
+	BusinessRatio    float64
+	OtherRatio       float64
+	TotalRatio       float64
+}
+
+// ReadZipCountyFile reads a crosswalk file in CSV or XLSX format, chosen by
+// the file's extension.
+func ReadZipCountyFile(fileName string, yield func(ZipCounty)) error {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch ext := strings.ToLower(filepath.Ext(fileName)); ext {
+	case ".csv":
+		return ReadZipCountyCSV(f, yield)
+	case ".xlsx":
+		fi, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		return ReadZipCountyXLSX(f, fi.Size(), yield)
+	default:
+		return fmt.Errorf("unsupported crosswalk file extension: %q", ext)
+	}
+}
+
+func ReadZipCountyCSV(r io.Reader, yield func(ZipCounty)) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read crosswalk header: %w", err)
+	}
+	p, err := newRowParser(header)
+	if err != nil {
+		return err
+	}
+
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		zc, err := p.parse(row)
+		if err != nil {
+			return err
+		}
+		yield(zc)
+	}
+
+	return nil
+}
+
+func ReadZipCountyXLSX(r io.ReaderAt, size int64, yield func(ZipCounty)) error {
+	var p *rowParser
+
+	return readXLSXRows(r, size, func(row []string) error {
+		if p == nil {
+			var err error
+			p, err = newRowParser(row)
+			return err
+		}
+
+		zc, err := p.parse(row)
+		if err != nil {
+			return err
+		}
+		yield(zc)
+		return nil
+	})
+}
+
+type rowParser struct {
+	columns map[string]int
+}
+
+var requiredColumns = []string{"ZIP", "COUNTY"}
+
+func newRowParser(header []string) (*rowParser, error) {
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToUpper(strings.TrimSpace(name))] = i
+	}
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("crosswalk is missing column %v", name)
+		}
+	}
+
+	return &rowParser{columns: columns}, nil
+}
+
+func (p *rowParser) parse(row []string) (ZipCounty, error) {
+	var zc ZipCounty
+	var err error
+
+	// Spreadsheets store codes as numbers, dropping leading zeros.
+	zc.ZipCode = padDigits(p.field(row, "ZIP"), 5)
+	zc.CountyFIPS = padDigits(p.field(row, "COUNTY"), 5)
+	zc.PreferredCity = p.field(row, "USPS_ZIP_PREF_CITY")
+	zc.PreferredState = p.field(row, "USPS_ZIP_PREF_STATE")
+
+	if zc.ResidentialRatio, err = p.ratio(row, "RES_RATIO"); err != nil {
+		return zc, err
+	}
+	if zc.BusinessRatio, err = p.ratio(row, "BUS_RATIO"); err != nil {
+		return zc, err
+	}
+	if zc.OtherRatio, err = p.ratio(row, "OTH_RATIO"); err != nil {
+		return zc, err
+	}
+	if zc.TotalRatio, err = p.ratio(row, "TOT_RATIO"); err != nil {
+		return zc, err
+	}
+
+	return zc, nil
+}
+
+func (p *rowParser) field(row []string, name string) string {
+	i, ok := p.columns[name]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+func (p *rowParser) ratio(row []string, name string) (float64, error) {
+	s := p.field(row, name)
+	if s == "" {
+		return 0, nil
+	}
+	r, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %v: %q", name, s)
+	}
+	return r, nil
+}
+
+func padDigits(s string, n int) string {
+	if len(s) >= n {
+		return s
+	}
+	return strings.Repeat("0", n-len(s)) + s
+}
@@ -0,0 +1,162 @@
+package hud
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readCSV(t *testing.T, s string) []ZipCounty {
+	var zcs []ZipCounty
+	if err := ReadZipCountyCSV(strings.NewReader(s), func(zc ZipCounty) { zcs = append(zcs, zc) }); err != nil {
+		t.Fatal(err)
+	}
+	return zcs
+}
+
+func TestReadZipCountyCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+		want []ZipCounty
+	}{
+		{
+			"full row",
+			"ZIP,COUNTY,USPS_ZIP_PREF_CITY,USPS_ZIP_PREF_STATE,RES_RATIO,BUS_RATIO,OTH_RATIO,TOT_RATIO\n" +
+				"22201,51013,ARLINGTON,VA,1,0.5,0,0.9\n",
+			[]ZipCounty{{"22201", "51013", "ARLINGTON", "VA", 1, 0.5, 0, 0.9}},
+		},
+		{
+			"leading zeros dropped by a spreadsheet",
+			"ZIP,COUNTY,TOT_RATIO\n501,1001,1\n",
+			[]ZipCounty{{ZipCode: "00501", CountyFIPS: "01001", TotalRatio: 1}},
+		},
+		{
+			"header with BOM, lower case and spaces, columns reordered",
+			"\ufeffcounty, zip ,tot_ratio\n36103,11701,0.25\n",
+			[]ZipCounty{{ZipCode: "11701", CountyFIPS: "36103", TotalRatio: 0.25}},
+		},
+		{
+			"short row and empty ratio",
+			"ZIP,COUNTY,RES_RATIO,TOT_RATIO\n22201,51013,\n",
+			[]ZipCounty{{ZipCode: "22201", CountyFIPS: "51013"}},
+		},
+	}
+
+	for _, test := range tests {
+		if got := readCSV(t, test.csv); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%v: got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestReadZipCountyCSVErrors(t *testing.T) {
+	tests := map[string]string{
+		"missing COUNTY": "ZIP,TOT_RATIO\n22201,1\n",
+		"bad ratio":      "ZIP,COUNTY,TOT_RATIO\n22201,51013,most\n",
+		"empty":          "",
+	}
+	for name, s := range tests {
+		if err := ReadZipCountyCSV(strings.NewReader(s), func(ZipCounty) {}); err == nil {
+			t.Errorf("%v: expected error", name)
+		}
+	}
+}
+
+// xlsxFixture builds a workbook whose first sheet has a header row of shared
+// strings and data rows that mix shared strings, inline strings and
+// numbers, omitting empty cells the way Excel does.
+func xlsxFixture(t *testing.T) []byte {
+	files := map[string]string{
+		"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="ZIP_COUNTY" sheetId="1" r:id="rId2"/><sheet name="Notes" sheetId="2" r:id="rId1"/></sheets>
+</workbook>`,
+		"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet2.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet1.xml"/>
+</Relationships>`,
+		"xl/sharedStrings.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>ZIP</t></si><si><t>COUNTY</t></si><si><t>USPS_ZIP_PREF_CITY</t></si><si><t>USPS_ZIP_PREF_STATE</t></si>
+<si><t>RES_RATIO</t></si><si><t>TOT_RATIO</t></si><si><r><t>ARLING</t></r><r><t>TON</t></r></si>
+</sst>`,
+		"xl/worksheets/sheet1.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c><c r="D1" t="s"><v>3</v></c><c r="E1" t="s"><v>4</v></c><c r="F1" t="s"><v>5</v></c></row>
+<row r="2"><c r="A2"><v>22201</v></c><c r="B2"><v>51013</v></c><c r="C2" t="s"><v>6</v></c><c r="D2" t="inlineStr"><is><t>VA</t></is></c><c r="E2"><v>0.75</v></c><c r="F2"><v>1</v></c></row>
+<row r="3"><c r="A3"><v>501</v></c><c r="B3"><v>36103</v></c><c r="F3"><v>0.5</v></c></row>
+</sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>not the data</t></is></c></row></sheetData></worksheet>`,
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadZipCountyXLSX(t *testing.T) {
+	bz := xlsxFixture(t)
+
+	var got []ZipCounty
+	if err := ReadZipCountyXLSX(bytes.NewReader(bz), int64(len(bz)), func(zc ZipCounty) { got = append(got, zc) }); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ZipCounty{
+		{ZipCode: "22201", CountyFIPS: "51013", PreferredCity: "ARLINGTON", PreferredState: "VA", ResidentialRatio: 0.75, TotalRatio: 1},
+		{ZipCode: "00501", CountyFIPS: "36103", TotalRatio: 0.5},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestReadZipCountyFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"zip_county.csv":  []byte("ZIP,COUNTY,TOT_RATIO\n22201,51013,1\n"),
+		"ZIP_COUNTY.XLSX": xlsxFixture(t),
+	}
+	for name, content := range files {
+		fileName := filepath.Join(dir, name)
+		if err := os.WriteFile(fileName, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		var zips []string
+		if err := ReadZipCountyFile(fileName, func(zc ZipCounty) { zips = append(zips, zc.ZipCode) }); err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+		if len(zips) == 0 || zips[0] != "22201" {
+			t.Errorf("%v: got %v", name, zips)
+		}
+	}
+
+	if err := ReadZipCountyFile(filepath.Join(dir, "zip_county.txt"), func(ZipCounty) {}); err == nil {
+		t.Error("expected error for missing file")
+	}
+	txt := filepath.Join(dir, "zip_county.tsv")
+	os.WriteFile(txt, nil, 0644)
+	if err := ReadZipCountyFile(txt, func(ZipCounty) {}); err == nil || !strings.Contains(err.Error(), "unsupported") {
+		t.Errorf("expected unsupported extension error, got %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package hud
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// readXLSXRows calls yield with the cell values of each row of the first
+// worksheet in an XLSX workbook. Only the parts of the format used by the
+// HUD crosswalk files are supported.
+func readXLSXRows(r io.ReaderAt, size int64, yield func([]string) error) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	sheetName, err := firstSheetName(files)
+	if err != nil {
+		return err
+	}
+
+	var sharedStrings []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		sharedStrings, err = readSharedStrings(f)
+		if err != nil {
+			return err
+		}
+	}
+
+	sheet, ok := files[sheetName]
+	if !ok {
+		return fmt.Errorf("worksheet not found in workbook: %v", sheetName)
+	}
+	rc, err := sheet.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	d := xml.NewDecoder(rc)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		var row xlsxRow
+		if err := d.DecodeElement(&row, &start); err != nil {
+			return err
+		}
+
+		values, err := row.values(sharedStrings)
+		if err != nil {
+			return err
+		}
+		if err := yield(values); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSharedStrings struct {
+	Items []struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"si"`
+}
+
+type xlsxRow struct {
+	Cells []struct {
+		Ref        string `xml:"r,attr"`
+		Type       string `xml:"t,attr"`
+		Value      string `xml:"v"`
+		InlineText string `xml:"is>t"`
+	} `xml:"c"`
+}
+
+func firstSheetName(files map[string]*zip.File) (string, error) {
+	var wb xlsxWorkbook
+	if err := decodeXMLFile(files, "xl/workbook.xml", &wb); err != nil {
+		return "", err
+	}
+	if len(wb.Sheets) == 0 {
+		return "", fmt.Errorf("workbook has no sheets")
+	}
+
+	var rels xlsxRelationships
+	if err := decodeXMLFile(files, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID == wb.Sheets[0].ID {
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/"), nil
+			}
+			return path.Join("xl", rel.Target), nil
+		}
+	}
+
+	return "", fmt.Errorf("relationship not found for sheet: %v", wb.Sheets[0].ID)
+}
+
+func readSharedStrings(f *zip.File) ([]string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var sst xlsxSharedStrings
+	if err := xml.NewDecoder(rc).Decode(&sst); err != nil {
+		return nil, err
+	}
+
+	strs := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		if len(item.Runs) == 0 {
+			strs[i] = item.Text
+			continue
+		}
+		var sb strings.Builder
+		for _, run := range item.Runs {
+			sb.WriteString(run.Text)
+		}
+		strs[i] = sb.String()
+	}
+
+	return strs, nil
+}
+
+func decodeXMLFile(files map[string]*zip.File, name string, v any) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("not found in workbook: %v", name)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return xml.NewDecoder(rc).Decode(v)
+}
+
+func (row xlsxRow) values(sharedStrings []string) ([]string, error) {
+	var values []string
+
+	for _, c := range row.Cells {
+		col := len(values)
+		if c.Ref != "" {
+			var err error
+			col, err = columnIndex(c.Ref)
+			if err != nil {
+				return nil, err
+			}
+		}
+		// Empty cells are omitted from the sheet, so fill the gap.
+		for len(values) <= col {
+			values = append(values, "")
+		}
+
+		switch c.Type {
+		case "s":
+			i, err := strconv.Atoi(c.Value)
+			if err != nil || i < 0 || i >= len(sharedStrings) {
+				return nil, fmt.Errorf("invalid shared string index in cell %v: %q", c.Ref, c.Value)
+			}
+			values[col] = sharedStrings[i]
+		case "inlineStr":
+			values[col] = c.InlineText
+		default:
+			values[col] = c.Value
+		}
+	}
+
+	return values, nil
+}
+
+// columnIndex returns the zero-based column of a cell reference like "AB12".
+func columnIndex(ref string) (int, error) {
+	col := 0
+	i := 0
+	for ; i < len(ref) && ref[i] >= 'A' && ref[i] <= 'Z'; i++ {
+		col = col*26 + int(ref[i]-'A'+1)
+	}
+	if i == 0 {
+		return 0, fmt.Errorf("invalid cell reference: %q", ref)
+	}
+	return col - 1, nil
+}
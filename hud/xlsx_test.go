@@ -0,0 +1,48 @@
+package hud
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+func TestColumnIndex(t *testing.T) {
+	tests := map[string]int{
+		"A1":    0,
+		"B12":   1,
+		"Z3":    25,
+		"AA1":   26,
+		"AB100": 27,
+		"XFD1":  16383,
+	}
+	for ref, want := range tests {
+		if got, err := columnIndex(ref); err != nil || got != want {
+			t.Errorf("%v: got %v, %v, want %v", ref, got, err, want)
+		}
+	}
+	if _, err := columnIndex("12"); err == nil {
+		t.Error("expected error for reference without a column")
+	}
+}
+
+func TestRowValues(t *testing.T) {
+	// B and D are shared and inline strings, A and C are empty and omitted,
+	// and the last cell has no reference so follows the one before it.
+	var row xlsxRow
+	err := xml.Unmarshal([]byte(`<row r="1"><c r="B1" t="s"><v>1</v></c><c r="D1" t="inlineStr"><is><t>inline</t></is></c><c><v>42</v></c></row>`), &row)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := row.values([]string{"zero", "one"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"", "one", "", "inline", "42"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := row.values([]string{"zero"}); err == nil {
+		t.Error("expected error for out-of-range shared string")
+	}
+}
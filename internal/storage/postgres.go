@@ -0,0 +1,351 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// copyBufferRows is how many rows are queued for COPY while records are
+// read.
+const copyBufferRows = 4096
+
+const postgresLoadMetadataCreateTableQuery = `CREATE TABLE load_metadata(
+												Id BIGSERIAL PRIMARY KEY,
+												Dataset TEXT NOT NULL,
+												SourceFile TEXT,
+												FulfillmentDate TEXT,
+												RecordCount INTEGER NOT NULL,
+												Release TEXT,
+												LoadedAt BIGINT NOT NULL)`
+
+// references matches foreign key clauses. PostgreSQL enforces them, and
+// bulk loads add states and counties after the rows that reference them,
+// so they're left out.
+var references = regexp.MustCompile(` REFERENCES \w+\(\w+\)`)
+
+var errCopyAborted = errors.New("copy aborted")
+
+type postgresDialect struct{}
+
+// NewPostgres returns a store backed by a PostgreSQL database opened with
+// the pgx driver.
+func NewPostgres(db *sql.DB) Store {
+	return &sqlStore{db: db, d: postgresDialect{}}
+}
+
+func (postgresDialect) rebind(query string) string {
+	var sb strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			sb.WriteString("$" + strconv.Itoa(n))
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func (postgresDialect) tableExists(q queryer, table string) (bool, error) {
+	var n int
+	err := q.QueryRow(`SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = $1`, table).Scan(&n)
+	return n > 0, err
+}
+
+func (postgresDialect) createTableQueries() []string {
+	queries := []string{
+		statesCreateTableQuery,
+		countiesCreateTableQuery,
+		zipsCreateTableQuery,
+		cityStateKeysCreateTableQuery,
+		zip4RangesCreateTableQuery,
+	}
+	for i, query := range queries {
+		queries[i] = references.ReplaceAllString(query, "")
+	}
+	return append(queries, postgresLoadMetadataCreateTableQuery)
+}
+
+func (postgresDialect) currentReleaseQuery() string {
+	return `SELECT Release FROM load_metadata WHERE Release IS NOT NULL AND Release != '' ORDER BY Id DESC LIMIT 1`
+}
+
+func (postgresDialect) migrateLegacy(tx *sql.Tx) error {
+	return nil
+}
+
+// tune turns off synchronous commit. Settings are per session, so the
+// database is limited to one connection.
+func (postgresDialect) tune(db *sql.DB) error {
+	db.SetMaxOpenConns(1)
+	_, err := db.Exec(`SET synchronous_commit = off`)
+	return err
+}
+
+// bulkLoad streams rows into the table with COPY, one COPY per batchSize
+// rows.
+func (postgresDialect) bulkLoad(db *sql.DB, table string, columns []string, batchSize int, rows func(add func([]any) error) error) (int, error) {
+	ctx := context.Background()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	// Unquoted identifiers are folded to lower case, and COPY quotes them.
+	copyColumns := make([]string, len(columns))
+	for i, column := range columns {
+		copyColumns[i] = strings.ToLower(column)
+	}
+
+	rowc := make(chan []any, copyBufferRows)
+	stop := make(chan struct{})
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(rowc)
+		readErr <- rows(func(row []any) error {
+			select {
+			case rowc <- row:
+				return nil
+			case <-stop:
+				return errCopyAborted
+			}
+		})
+	}()
+
+	p := newProgress(table)
+	copyErr := conn.Raw(func(driverConn any) error {
+		pc := driverConn.(*stdlib.Conn).Conn()
+		for {
+			src := &chanSource{rows: rowc, limit: batchSize}
+			n, err := pc.CopyFrom(ctx, pgx.Identifier{table}, copyColumns, src)
+			if err != nil {
+				return err
+			}
+			p.add(int(n))
+			if src.closed {
+				return nil
+			}
+		}
+	})
+	close(stop)
+
+	if err := <-readErr; err != nil && !errors.Is(err, errCopyAborted) {
+		return 0, err
+	}
+	if copyErr != nil {
+		return 0, copyErr
+	}
+
+	p.done()
+	return p.rows, nil
+}
+
+// chanSource is a pgx.CopyFromSource that reads up to limit rows from a
+// channel.
+type chanSource struct {
+	rows   <-chan []any
+	limit  int
+	n      int
+	row    []any
+	closed bool
+}
+
+func (s *chanSource) Next() bool {
+	if s.n >= s.limit {
+		return false
+	}
+	row, ok := <-s.rows
+	if !ok {
+		s.closed = true
+		return false
+	}
+	s.row = row
+	s.n++
+	return true
+}
+
+func (s *chanSource) Values() ([]any, error) {
+	return s.row, nil
+}
+
+func (s *chanSource) Err() error {
+	return nil
+}
+
+// postgresVersionName names a previous version of a schema; 1 is the most
+// recent.
+func postgresVersionName(schema string, version int) string {
+	return fmt.Sprintf("%v_%d", schema, version)
+}
+
+// liveSchema returns the schema tables are created in: the first schema on
+// the search path.
+func liveSchema(db *sql.DB) (string, error) {
+	var schema sql.NullString
+	if err := db.QueryRow(`SELECT current_schema()`).Scan(&schema); err != nil {
+		return "", err
+	}
+	if !schema.Valid {
+		return "", fmt.Errorf("no schema on the search path exists")
+	}
+	return schema.String, nil
+}
+
+func schemaExists(q queryer, schema string) (bool, error) {
+	var n int
+	err := q.QueryRow(`SELECT COUNT(*) FROM pg_namespace WHERE nspname = $1`, schema).Scan(&n)
+	return n > 0, err
+}
+
+func quoteIdentifier(name string) string {
+	return pgx.Identifier{name}.Sanitize()
+}
+
+func openPostgresStaging(dsn string) (*Staging, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	live, err := liveSchema(db)
+	if err != nil {
+		return nil, err
+	}
+	staging := live + "_staging"
+	if _, err := db.Exec(`DROP SCHEMA IF EXISTS ` + quoteIdentifier(staging) + ` CASCADE`); err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(`CREATE SCHEMA ` + quoteIdentifier(staging)); err != nil {
+		return nil, err
+	}
+
+	config, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	config.RuntimeParams["search_path"] = staging
+
+	s := &sqlStore{db: stdlib.OpenDB(*config), d: postgresDialect{}}
+	return &Staging{sqlStore: s, promote: func(keep int) error {
+		if err := s.Close(); err != nil {
+			return err
+		}
+		return promotePostgres(dsn, live, staging, keep)
+	}}, nil
+}
+
+// promotePostgres renames the staging schema to the live one in a
+// transaction, so readers see either the old tables or the new ones. The
+// live schema becomes the most recent previous version.
+func promotePostgres(dsn, live, staging string, keep int) error {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if keep > 0 {
+		if _, err := tx.Exec(`DROP SCHEMA IF EXISTS ` + quoteIdentifier(postgresVersionName(live, keep)) + ` CASCADE`); err != nil {
+			return err
+		}
+		for v := keep - 1; v >= 1; v-- {
+			if err := renameSchemaIfExists(tx, postgresVersionName(live, v), postgresVersionName(live, v+1)); err != nil {
+				return err
+			}
+		}
+		if err := renameSchemaIfExists(tx, live, postgresVersionName(live, 1)); err != nil {
+			return err
+		}
+	} else if _, err := tx.Exec(`DROP SCHEMA IF EXISTS ` + quoteIdentifier(live) + ` CASCADE`); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`ALTER SCHEMA ` + quoteIdentifier(staging) + ` RENAME TO ` + quoteIdentifier(live)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	log.Printf("Promoted schema %v to %v.\n", staging, live)
+	return nil
+}
+
+func rollbackPostgres(dsn string) error {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	live, err := liveSchema(db)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	exists, err := schemaExists(tx, postgresVersionName(live, 1))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("no previous version of schema %v", live)
+	}
+
+	if _, err := tx.Exec(`DROP SCHEMA ` + quoteIdentifier(live) + ` CASCADE`); err != nil {
+		return err
+	}
+	if err := renameSchemaIfExists(tx, postgresVersionName(live, 1), live); err != nil {
+		return err
+	}
+	for v := 2; ; v++ {
+		exists, err := schemaExists(tx, postgresVersionName(live, v))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			break
+		}
+		if err := renameSchemaIfExists(tx, postgresVersionName(live, v), postgresVersionName(live, v-1)); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	log.Printf("Rolled schema %v back to its previous version.\n", live)
+	return nil
+}
+
+func renameSchemaIfExists(tx *sql.Tx, from, to string) error {
+	exists, err := schemaExists(tx, from)
+	if err != nil || !exists {
+		return err
+	}
+	_, err = tx.Exec(`ALTER SCHEMA ` + quoteIdentifier(from) + ` RENAME TO ` + quoteIdentifier(to))
+	return err
+}
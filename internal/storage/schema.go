@@ -0,0 +1,321 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/corbaltcode/usps/citystate"
+	"github.com/corbaltcode/usps/fips"
+	"github.com/corbaltcode/usps/zip4"
+)
+
+// The schema is normalized:
+//
+//   - states and counties hold each state and county once, keyed by
+//     abbreviation and five-digit FIPS code
+//   - zips holds one row per ZIP from the City State data
+//   - city_state_keys holds the City State detail records, one per ZIP and
+//     city state key
+//   - zip4_ranges holds the ZIP+4 detail records with add-ons as integers;
+//     non-deliverable ("ND") ranges have NULL add-ons and Deliverable 0
+//   - load_metadata records each load and applied change file, with the
+//     record count from the file's header if it was loaded in full
+//   - schema_version records which migrations have run
+
+const statesCreateTableQuery = `CREATE TABLE states(
+									StateAbbreviation TEXT PRIMARY KEY,
+									StateFips TEXT NOT NULL)`
+
+const countiesCreateTableQuery = `CREATE TABLE counties(
+									CountyFips TEXT PRIMARY KEY,
+									StateAbbreviation TEXT NOT NULL REFERENCES states(StateAbbreviation),
+									CountyNumber TEXT NOT NULL,
+									CountyName TEXT)`
+
+const zipsCreateTableQuery = `CREATE TABLE zips(
+								ZipCode TEXT PRIMARY KEY,
+								ZipClassificationCode TEXT,
+								StateAbbreviation TEXT NOT NULL REFERENCES states(StateAbbreviation),
+								CountyFips TEXT REFERENCES counties(CountyFips),
+								PreferredLastLineCityStateKey TEXT,
+								PreferredLastLineCityStateName TEXT)`
+
+const cityStateKeysCreateTableQuery = `CREATE TABLE city_state_keys(
+										ZipCode TEXT NOT NULL,
+										CityStateKey TEXT NOT NULL,
+										CityStateName TEXT NOT NULL,
+										CityStateNameAbbreviation TEXT,
+										CityStateNameFacilityCode TEXT,
+										CityStateMailingNameIndicator TEXT,
+										PreferredLastLineCityStateKey TEXT,
+										PreferredLastLineCityStateName TEXT,
+										CityDeliveryIndicator TEXT,
+										CarrierRouteRateSortation TEXT,
+										UniqueZipNameIndicator TEXT,
+										FinanceNumber TEXT,
+										StateAbbreviation TEXT NOT NULL)`
+
+const zip4RangesCreateTableQuery = `CREATE TABLE zip4_ranges(
+									ZipCode TEXT NOT NULL,
+									UpdateKey TEXT,
+									RecordTypeCode TEXT NOT NULL,
+									CountyFips TEXT REFERENCES counties(CountyFips),
+									Sector TEXT NOT NULL,
+									Plus4Low INTEGER,
+									Plus4High INTEGER,
+									Deliverable INTEGER NOT NULL)`
+
+const sqliteLoadMetadataCreateTableQuery = `CREATE TABLE load_metadata(
+										Dataset TEXT NOT NULL,
+										SourceFile TEXT,
+										FulfillmentDate TEXT,
+										RecordCount INTEGER NOT NULL,
+										Release TEXT,
+										LoadedAt INTEGER NOT NULL)`
+
+var zip4RangeColumns = []string{"ZipCode", "UpdateKey", "RecordTypeCode", "CountyFips", "Sector", "Plus4Low", "Plus4High", "Deliverable"}
+var zip4RangeInsertQuery = insertQuery("zip4_ranges", zip4RangeColumns, 1)
+
+// zip4RangeIndexes are dropped before and built after a load.
+var zip4RangeIndexes = map[string]string{
+	"zip4_ranges_zip":        `CREATE INDEX IF NOT EXISTS zip4_ranges_zip ON zip4_ranges(ZipCode, Plus4Low)`,
+	"zip4_ranges_update_key": `CREATE INDEX IF NOT EXISTS zip4_ranges_update_key ON zip4_ranges(UpdateKey)`,
+	"zip4_ranges_county":     `CREATE INDEX IF NOT EXISTS zip4_ranges_county ON zip4_ranges(CountyFips)`,
+}
+
+var cityStateKeyColumns = []string{"ZipCode", "CityStateKey", "CityStateName", "CityStateNameAbbreviation", "CityStateNameFacilityCode", "CityStateMailingNameIndicator", "PreferredLastLineCityStateKey", "PreferredLastLineCityStateName", "CityDeliveryIndicator", "CarrierRouteRateSortation", "UniqueZipNameIndicator", "FinanceNumber", "StateAbbreviation"}
+var cityStateKeyInsertQuery = insertQuery("city_state_keys", cityStateKeyColumns, 1)
+
+var cityStateKeyIndexes = map[string]string{
+	"city_state_keys_zip":  `CREATE INDEX IF NOT EXISTS city_state_keys_zip ON city_state_keys(ZipCode)`,
+	"city_state_keys_city": `CREATE INDEX IF NOT EXISTS city_state_keys_city ON city_state_keys(StateAbbreviation, CityStateName)`,
+}
+
+const zipsCountyIndexQuery = `CREATE INDEX IF NOT EXISTS zips_county ON zips(CountyFips)`
+
+const zipUpsertQuery = `INSERT INTO zips(ZipCode,ZipClassificationCode,StateAbbreviation,CountyFips,PreferredLastLineCityStateKey,PreferredLastLineCityStateName) VALUES(?,?,?,?,?,?)
+						ON CONFLICT(ZipCode) DO UPDATE SET ZipClassificationCode = excluded.ZipClassificationCode, StateAbbreviation = excluded.StateAbbreviation, CountyFips = excluded.CountyFips,
+						PreferredLastLineCityStateKey = excluded.PreferredLastLineCityStateKey, PreferredLastLineCityStateName = excluded.PreferredLastLineCityStateName`
+const stateUpsertQuery = `INSERT INTO states(StateAbbreviation,StateFips) VALUES(?,?) ON CONFLICT(StateAbbreviation) DO NOTHING`
+const countyUpsertQuery = `INSERT INTO counties(CountyFips,StateAbbreviation,CountyNumber,CountyName) VALUES(?,?,?,?)
+							ON CONFLICT(CountyFips) DO UPDATE SET CountyName = COALESCE(excluded.CountyName, counties.CountyName)`
+
+const loadMetadataInsertQuery = `INSERT INTO load_metadata(Dataset,SourceFile,FulfillmentDate,RecordCount,ExpectedRecordCount,Release,LoadedAt) VALUES(?,?,?,?,?,?,?)`
+
+// migrations bring a database from one schema version to the next;
+// migrations[i] takes version i to version i+1. Version 0 is either an
+// empty database or the original zip4_data and city_state tables.
+var migrations = []func(tx *sql.Tx, d dialect) error{
+	migrateNormalizedSchema,
+	migrateExpectedRecordCount,
+}
+
+// migrate runs the migrations a database hasn't had, each in its own
+// transaction.
+func migrate(db *sql.DB, d dialect) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_version(Version INTEGER NOT NULL)`); err != nil {
+		return err
+	}
+
+	var version int
+	err := db.QueryRow(`SELECT Version FROM schema_version`).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		if _, err := db.Exec(`INSERT INTO schema_version(Version) VALUES(0)`); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if version > len(migrations) {
+		return fmt.Errorf("database schema version %v is newer than this program's (%v)", version, len(migrations))
+	}
+
+	for ; version < len(migrations); version++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+
+		if err := migrations[version](tx, d); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrating schema to version %v: %v", version+1, err)
+		}
+		if _, err := tx.Exec(d.rebind(`UPDATE schema_version SET Version = ?`), version+1); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+
+		log.Printf("Migrated schema to version %v.\n", version+1)
+	}
+
+	return nil
+}
+
+// migrateNormalizedSchema creates the normalized tables and, in SQLite,
+// moves any rows from the original zip4_data, city_state and release_info
+// tables into them.
+func migrateNormalizedSchema(tx *sql.Tx, d dialect) error {
+	queries := append(d.createTableQueries(), zipsCountyIndexQuery)
+	for _, index := range zip4RangeIndexes {
+		queries = append(queries, index)
+	}
+	for _, index := range cityStateKeyIndexes {
+		queries = append(queries, index)
+	}
+	for _, query := range queries {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+
+	return d.migrateLegacy(tx)
+}
+
+// migrateExpectedRecordCount adds the record counts from file headers to
+// load_metadata, for validating loads.
+func migrateExpectedRecordCount(tx *sql.Tx, d dialect) error {
+	_, err := tx.Exec(`ALTER TABLE load_metadata ADD COLUMN ExpectedRecordCount INTEGER`)
+	return err
+}
+
+// geography collects the states, counties and ZIPs seen while loading
+// records, for saving once the records are loaded.
+type geography struct {
+	states   map[string]string
+	counties map[string]county
+	zips     map[string][]any
+	zipOrder []string
+}
+
+type county struct {
+	state  string
+	number string
+	name   sql.NullString
+}
+
+func newGeography() *geography {
+	return &geography{
+		states:   make(map[string]string),
+		counties: make(map[string]county),
+		zips:     make(map[string][]any),
+	}
+}
+
+func (g *geography) addCounty(state, number, name string) (string, error) {
+	code, err := fips.CountyCode(state, number)
+	if err != nil {
+		return "", err
+	}
+
+	g.states[state] = code[:2]
+	c := g.counties[code]
+	c.state, c.number = state, number
+	if name != "" {
+		c.name = sql.NullString{String: name, Valid: true}
+	}
+	g.counties[code] = c
+
+	return code, nil
+}
+
+// zip4RangeRow returns the zip4_ranges row for a ZIP+4 record.
+func (g *geography) zip4RangeRow(d zip4.Zip4Detail) ([]any, error) {
+	county, err := g.addCounty(d.StateAbbreviation, d.CountyNumber, "")
+	if err != nil {
+		return nil, fmt.Errorf("ZIP %v: %v", d.ZipCode, err)
+	}
+
+	var low, high sql.NullInt64
+	if n, err := d.Plus4LowNumber.Int(); err == nil {
+		low = sql.NullInt64{Int64: int64(n), Valid: true}
+	}
+	if n, err := d.Plus4HighNumber.Int(); err == nil {
+		high = sql.NullInt64{Int64: int64(n), Valid: true}
+	}
+
+	var updateKey sql.NullString
+	if d.UpdateKey != "" {
+		updateKey = sql.NullString{String: d.UpdateKey, Valid: true}
+	}
+
+	deliverable := 0
+	if d.Plus4LowNumber.IsDeliverable() && d.Plus4HighNumber.IsDeliverable() {
+		deliverable = 1
+	}
+
+	return []any{d.ZipCode, updateKey, d.RecordTypeCode, county, d.Plus4LowNumber.Sector(), low, high, deliverable}, nil
+}
+
+// addCityState records the ZIP and county of a City State record.
+func (g *geography) addCityState(d citystate.CityStateDetail) error {
+	county, err := g.addCounty(d.StateAbbreviation, d.CountyNumber, d.CountyName)
+	if err != nil {
+		return fmt.Errorf("ZIP %v: %v", d.ZipCode, err)
+	}
+
+	if _, ok := g.zips[d.ZipCode]; !ok {
+		g.zipOrder = append(g.zipOrder, d.ZipCode)
+	}
+	g.zips[d.ZipCode] = []any{d.ZipCode, d.ZipClassificationCode, d.StateAbbreviation, county, d.PreferredLastLineCityStateKey, d.PreferredLastLineCityStateName}
+
+	return nil
+}
+
+// save upserts the states, counties and ZIPs seen.
+func (g *geography) save(db execer) error {
+	for state, code := range g.states {
+		if _, err := db.Exec(stateUpsertQuery, state, code); err != nil {
+			return err
+		}
+	}
+	for code, c := range g.counties {
+		if _, err := db.Exec(countyUpsertQuery, code, c.state, c.number, c.name); err != nil {
+			return err
+		}
+	}
+	for _, zip := range g.zipOrder {
+		if _, err := db.Exec(zipUpsertQuery, g.zips[zip]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cityStateKeyRow returns the city_state_keys row for a City State record.
+func cityStateKeyRow(d citystate.CityStateDetail) []any {
+	return []any{
+		d.ZipCode,
+		d.CityStateKey,
+		d.CityStateName,
+		d.CityStateNameAbbreviation,
+		d.CityStateNameFacilityCode,
+		d.CityStateMailingNameIndicator,
+		d.PreferredLastLineCityStateKey,
+		d.PreferredLastLineCityStateName,
+		d.CityDeliveryIndicator,
+		d.CarrierRouteRateSortation,
+		d.UniqueZipNameIndicator,
+		d.FinanceNumber,
+		d.StateAbbreviation,
+	}
+}
+
+func recordLoad(db execer, m LoadMetadata) error {
+	var fulfillmentDate sql.NullString
+	if !m.FulfillmentDate.IsZero() {
+		fulfillmentDate = sql.NullString{String: m.FulfillmentDate.UTC().Format(time.DateOnly), Valid: true}
+	}
+	var expected sql.NullInt64
+	if m.ExpectedRecordCount > 0 {
+		expected = sql.NullInt64{Int64: int64(m.ExpectedRecordCount), Valid: true}
+	}
+	_, err := db.Exec(loadMetadataInsertQuery, m.Dataset, m.SourceFile, fulfillmentDate, m.RecordCount, expected, m.Release, time.Now().Unix())
+	return err
+}
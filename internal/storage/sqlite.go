@@ -0,0 +1,452 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/corbaltcode/usps/fips"
+)
+
+// rowsPerInsert is how many rows go in each multi-row INSERT. It keeps the
+// widest table (city_state_keys) well under SQLite's limit on bound parameters.
+const rowsPerInsert = 500
+
+// bulkPragmas trade durability for speed while loading. A failed load
+// leaves a database that should be thrown away and reloaded.
+var bulkPragmas = []string{
+	`PRAGMA journal_mode = MEMORY`,
+	`PRAGMA synchronous = OFF`,
+	`PRAGMA temp_store = MEMORY`,
+	`PRAGMA cache_size = -262144`,
+}
+
+type sqliteDialect struct{}
+
+// NewSQLite returns a store backed by a SQLite database.
+func NewSQLite(db *sql.DB) Store {
+	return &sqlStore{db: db, d: sqliteDialect{}}
+}
+
+func (sqliteDialect) rebind(query string) string {
+	return query
+}
+
+func (sqliteDialect) tableExists(q queryer, table string) (bool, error) {
+	var n int
+	err := q.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&n)
+	return n > 0, err
+}
+
+func (sqliteDialect) createTableQueries() []string {
+	return []string{
+		statesCreateTableQuery,
+		countiesCreateTableQuery,
+		zipsCreateTableQuery,
+		cityStateKeysCreateTableQuery,
+		zip4RangesCreateTableQuery,
+		sqliteLoadMetadataCreateTableQuery,
+	}
+}
+
+func (sqliteDialect) currentReleaseQuery() string {
+	return `SELECT Release FROM load_metadata WHERE Release IS NOT NULL AND Release != '' ORDER BY rowid DESC LIMIT 1`
+}
+
+// tune applies bulkPragmas. Pragmas are per connection, so the database is
+// limited to one.
+func (sqliteDialect) tune(db *sql.DB) error {
+	db.SetMaxOpenConns(1)
+	for _, pragma := range bulkPragmas {
+		if _, err := db.Exec(pragma); err != nil {
+			return fmt.Errorf("%v: %v", pragma, err)
+		}
+	}
+	return nil
+}
+
+func (sqliteDialect) bulkLoad(db *sql.DB, table string, columns []string, batchSize int, rows func(add func([]any) error) error) (int, error) {
+	loader := newBulkLoader(db, table, columns, batchSize)
+
+	if err := rows(loader.Add); err != nil {
+		loader.Abort()
+		return 0, err
+	}
+
+	if err := loader.Close(); err != nil {
+		return 0, err
+	}
+
+	return loader.progress.rows, nil
+}
+
+// migrateLegacy moves rows from the zip4_data, city_state and release_info
+// tables seed-db created before schema_version.
+func (d sqliteDialect) migrateLegacy(tx *sql.Tx) error {
+	if ok, err := d.tableExists(tx, "zip4_data"); err != nil {
+		return err
+	} else if ok {
+		if err := migrateLegacyZip4(tx); err != nil {
+			return err
+		}
+	}
+
+	if ok, err := d.tableExists(tx, "city_state"); err != nil {
+		return err
+	} else if ok {
+		if err := migrateLegacyCityState(tx); err != nil {
+			return err
+		}
+	}
+
+	if ok, err := d.tableExists(tx, "release_info"); err != nil {
+		return err
+	} else if ok {
+		_, err := tx.Exec(`INSERT INTO load_metadata(Dataset,RecordCount,Release,LoadedAt) SELECT 'legacy', 0, Release, AppliedAt FROM release_info ORDER BY rowid`)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`DROP TABLE release_info`); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrateLegacyStates fills states from the abbreviations in a legacy
+// table, so the other legacy rows can be moved with INSERT ... SELECT.
+func migrateLegacyStates(tx *sql.Tx, table string) error {
+	rows, err := tx.Query(`SELECT DISTINCT StateAbbreviation FROM ` + table)
+	if err != nil {
+		return err
+	}
+	var states []string
+	for rows.Next() {
+		var state string
+		if err := rows.Scan(&state); err != nil {
+			rows.Close()
+			return err
+		}
+		states = append(states, state)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, state := range states {
+		code, ok := fips.StateCode(state)
+		if !ok {
+			return fmt.Errorf("%v: unknown state abbreviation: %q", table, state)
+		}
+		if _, err := tx.Exec(stateUpsertQuery, state, code); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func migrateLegacyZip4(tx *sql.Tx) error {
+	if err := migrateLegacyStates(tx, "zip4_data"); err != nil {
+		return err
+	}
+
+	// zip4_data only has update keys if it was seeded after they were
+	// parsed.
+	updateKey := "NULL"
+	if _, err := tx.Exec(`SELECT UpdateKey FROM zip4_data LIMIT 0`); err == nil {
+		updateKey = "z.UpdateKey"
+	}
+
+	queries := []string{
+		`INSERT INTO counties(CountyFips,StateAbbreviation,CountyNumber)
+			SELECT DISTINCT s.StateFips || z.CountyNumber, z.StateAbbreviation, z.CountyNumber
+			FROM zip4_data z JOIN states s ON s.StateAbbreviation = z.StateAbbreviation
+			WHERE true ON CONFLICT(CountyFips) DO NOTHING`,
+		`INSERT INTO zip4_ranges(ZipCode,UpdateKey,RecordTypeCode,CountyFips,Sector,Plus4Low,Plus4High,Deliverable)
+			SELECT z.ZipCode, ` + updateKey + `, z.RecordTypeCode, s.StateFips || z.CountyNumber, substr(z.Plus4LowNumber, 1, 2),
+				CASE WHEN z.Plus4LowNumber GLOB '[0-9][0-9][0-9][0-9]' THEN CAST(z.Plus4LowNumber AS INTEGER) END,
+				CASE WHEN z.Plus4HighNumber GLOB '[0-9][0-9][0-9][0-9]' THEN CAST(z.Plus4HighNumber AS INTEGER) END,
+				substr(z.Plus4LowNumber, 3, 2) != 'ND' AND substr(z.Plus4HighNumber, 3, 2) != 'ND'
+			FROM zip4_data z JOIN states s ON s.StateAbbreviation = z.StateAbbreviation`,
+		`DROP TABLE zip4_data`,
+	}
+	for _, query := range queries {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Migrated zip4_data to zip4_ranges.\n")
+	return nil
+}
+
+func migrateLegacyCityState(tx *sql.Tx) error {
+	if err := migrateLegacyStates(tx, "city_state"); err != nil {
+		return err
+	}
+
+	queries := []string{
+		`INSERT INTO counties(CountyFips,StateAbbreviation,CountyNumber,CountyName)
+			SELECT s.StateFips || c.CountyNumber, c.StateAbbreviation, c.CountyNumber, max(c.CountyName)
+			FROM city_state c JOIN states s ON s.StateAbbreviation = c.StateAbbreviation
+			GROUP BY 1 ON CONFLICT(CountyFips) DO UPDATE SET CountyName = excluded.CountyName`,
+		`INSERT INTO zips(ZipCode,ZipClassificationCode,StateAbbreviation,CountyFips,PreferredLastLineCityStateKey,PreferredLastLineCityStateName)
+			SELECT c.ZipCode, c.ZipClassificationCode, c.StateAbbreviation, s.StateFips || c.CountyNumber, c.PreferredLastLineCityStateKey, c.PreferredLastLineCityStateName
+			FROM city_state c JOIN states s ON s.StateAbbreviation = c.StateAbbreviation
+			GROUP BY c.ZipCode`,
+		`INSERT INTO city_state_keys(` + strings.Join(cityStateKeyColumns, ",") + `)
+			SELECT ` + strings.Join(cityStateKeyColumns, ",") + ` FROM city_state`,
+		`DROP TABLE city_state`,
+	}
+	for _, query := range queries {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Migrated city_state to zips and city_state_keys.\n")
+	return nil
+}
+
+func insertQuery(table string, columns []string, rows int) string {
+	row := "(" + strings.TrimSuffix(strings.Repeat("?,", len(columns)), ",") + ")"
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "INSERT INTO %v(%v) VALUES", table, strings.Join(columns, ","))
+	for i := 0; i < rows; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(row)
+	}
+	return sb.String()
+}
+
+// bulkLoader inserts rows into a table with prepared multi-row INSERTs,
+// committing every batchSize rows.
+type bulkLoader struct {
+	db        *sql.DB
+	table     string
+	columns   []string
+	batchSize int
+
+	tx       *sql.Tx
+	stmt     *sql.Stmt
+	pending  []any
+	inTx     int
+	progress *progress
+}
+
+func newBulkLoader(db *sql.DB, table string, columns []string, batchSize int) *bulkLoader {
+	return &bulkLoader{
+		db:        db,
+		table:     table,
+		columns:   columns,
+		batchSize: batchSize,
+		pending:   make([]any, 0, rowsPerInsert*len(columns)),
+		progress:  newProgress(table),
+	}
+}
+
+// Add queues a row, inserting and committing as batches fill.
+func (l *bulkLoader) Add(params []any) error {
+	if l.tx == nil {
+		if err := l.begin(); err != nil {
+			return err
+		}
+	}
+
+	l.pending = append(l.pending, params...)
+	if len(l.pending) < cap(l.pending) {
+		return nil
+	}
+
+	if _, err := l.stmt.Exec(l.pending...); err != nil {
+		return err
+	}
+	l.inserted(rowsPerInsert)
+	l.pending = l.pending[:0]
+
+	if l.inTx >= l.batchSize {
+		return l.commit()
+	}
+	return nil
+}
+
+// Close inserts any remaining rows and commits.
+func (l *bulkLoader) Close() error {
+	if l.tx == nil {
+		return nil
+	}
+
+	if len(l.pending) > 0 {
+		rows := len(l.pending) / len(l.columns)
+		if _, err := l.tx.Exec(insertQuery(l.table, l.columns, rows), l.pending...); err != nil {
+			l.tx.Rollback()
+			return err
+		}
+		l.inserted(rows)
+		l.pending = l.pending[:0]
+	}
+
+	if err := l.commit(); err != nil {
+		return err
+	}
+
+	l.progress.done()
+	return nil
+}
+
+// Abort rolls back rows not yet committed.
+func (l *bulkLoader) Abort() {
+	if l.tx != nil {
+		l.stmt.Close()
+		l.tx.Rollback()
+		l.tx = nil
+	}
+}
+
+func (l *bulkLoader) begin() error {
+	tx, err := l.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(insertQuery(l.table, l.columns, rowsPerInsert))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	l.tx, l.stmt, l.inTx = tx, stmt, 0
+	return nil
+}
+
+func (l *bulkLoader) commit() error {
+	l.stmt.Close()
+	err := l.tx.Commit()
+	l.tx, l.stmt = nil, nil
+	return err
+}
+
+func (l *bulkLoader) inserted(rows int) {
+	l.inTx += rows
+	l.progress.add(rows)
+}
+
+// progressInterval is how often progress is logged.
+const progressInterval = 10 * time.Second
+
+// progress logs rows loaded per second.
+type progress struct {
+	table      string
+	rows       int
+	start      time.Time
+	lastReport time.Time
+}
+
+func newProgress(table string) *progress {
+	now := time.Now()
+	return &progress{table: table, start: now, lastReport: now}
+}
+
+func (p *progress) add(rows int) {
+	p.rows += rows
+
+	now := time.Now()
+	if now.Sub(p.lastReport) >= progressInterval {
+		log.Printf("%v: %v rows (%.0f rows/s)\n", p.table, p.rows, float64(p.rows)/now.Sub(p.start).Seconds())
+		p.lastReport = now
+	}
+}
+
+func (p *progress) done() {
+	elapsed := time.Since(p.start)
+	log.Printf("%v: loaded %v rows in %v (%.0f rows/s)\n", p.table, p.rows, elapsed.Round(time.Second), float64(p.rows)/elapsed.Seconds())
+}
+
+// sqliteFileSuffixes are the database file and the files SQLite keeps
+// beside it.
+var sqliteFileSuffixes = []string{"", "-journal", "-wal", "-shm"}
+
+// sqliteVersionName names a previous version of a database; 1 is the most
+// recent.
+func sqliteVersionName(name string, version int) string {
+	return fmt.Sprintf("%v.%d", name, version)
+}
+
+func openSQLiteStaging(name string) (*Staging, error) {
+	staging := name + ".staging"
+	for _, suffix := range sqliteFileSuffixes {
+		if err := os.Remove(staging + suffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	db, err := sql.Open("sqlite3", staging)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &sqlStore{db: db, d: sqliteDialect{}}
+	return &Staging{sqlStore: s, promote: func(keep int) error {
+		if err := s.Close(); err != nil {
+			return err
+		}
+		return promoteSQLite(staging, name, keep)
+	}}, nil
+}
+
+// promoteSQLite renames staging over name. The live database is hard linked
+// as the most recent previous version first, so name always exists and
+// readers with it open keep reading the old version.
+func promoteSQLite(staging, name string, keep int) error {
+	if keep > 0 {
+		if err := os.Remove(sqliteVersionName(name, keep)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		for v := keep - 1; v >= 1; v-- {
+			err := os.Rename(sqliteVersionName(name, v), sqliteVersionName(name, v+1))
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+		err := os.Link(name, sqliteVersionName(name, 1))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	if err := os.Rename(staging, name); err != nil {
+		return err
+	}
+	log.Printf("Promoted %v to %v.\n", staging, name)
+	return nil
+}
+
+func rollbackSQLite(name string) error {
+	if err := os.Rename(sqliteVersionName(name, 1), name); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("no previous version of %v", name)
+		}
+		return err
+	}
+
+	for v := 2; ; v++ {
+		err := os.Rename(sqliteVersionName(name, v), sqliteVersionName(name, v-1))
+		if errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	log.Printf("Rolled %v back to its previous version.\n", name)
+	return nil
+}
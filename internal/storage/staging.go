@@ -0,0 +1,181 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// spotCheckZips is how many ZIPs Validate looks up.
+const spotCheckZips = 20
+
+// Staging is an empty database built beside a live one and then swapped in
+// for it, so readers never see a half-loaded database.
+type Staging struct {
+	*sqlStore
+	promote func(keep int) error
+}
+
+// OpenStaging opens a new, empty staging database for the database named
+// by dsn, replacing any left by an earlier failed build. For SQLite it's a
+// file beside the live one; for PostgreSQL it's a schema beside the live
+// schema.
+func OpenStaging(dsn string) (*Staging, error) {
+	if IsPostgres(dsn) {
+		return openPostgresStaging(dsn)
+	}
+	return openSQLiteStaging(strings.TrimPrefix(dsn, "sqlite:"))
+}
+
+// Promote closes the staging database and atomically swaps it in for the
+// live one, keeping up to keep previous versions for Rollback.
+func (s *Staging) Promote(keep int) error {
+	if keep < 0 {
+		return fmt.Errorf("invalid number of versions to keep: %v", keep)
+	}
+	return s.promote(keep)
+}
+
+// Rollback swaps the most recent previous version of the database named by
+// dsn back in, discarding the live one.
+func Rollback(dsn string) error {
+	if IsPostgres(dsn) {
+		return rollbackPostgres(dsn)
+	}
+	return rollbackSQLite(strings.TrimPrefix(dsn, "sqlite:"))
+}
+
+// Validate checks a freshly loaded database: each loaded table has as many
+// rows as the headers of the files loaded into it say they have records,
+// every range, ZIP and county has its county or state, and a sample of ZIPs
+// can be looked up. Loads without a header count, such as those limited to
+// some states, are only checked for being non-empty.
+func (s *Staging) Validate() error {
+	queryRow := func(query string, args ...any) *sql.Row {
+		return s.db.QueryRow(s.d.rebind(query), args...)
+	}
+
+	tables := []struct {
+		dataset Dataset
+		table   string
+	}{
+		{Zip4Dataset, "zip4_ranges"},
+		{CityStateDataset, "city_state_keys"},
+	}
+	loaded := make(map[Dataset]bool)
+	for _, t := range tables {
+		var loads, counted, expected int
+		err := queryRow(`SELECT COUNT(*), COUNT(ExpectedRecordCount), COALESCE(SUM(ExpectedRecordCount), 0) FROM load_metadata WHERE Dataset = ?`, string(t.dataset)).Scan(&loads, &counted, &expected)
+		if err != nil {
+			return err
+		}
+		if loads == 0 {
+			continue
+		}
+		loaded[t.dataset] = true
+
+		var rows int
+		if err := s.db.QueryRow(`SELECT COUNT(*) FROM ` + t.table).Scan(&rows); err != nil {
+			return err
+		}
+		if counted == loads && rows != expected {
+			return fmt.Errorf("validation failed: %v has %v rows but the file headers list %v records", t.table, rows, expected)
+		}
+		if rows == 0 {
+			return fmt.Errorf("validation failed: %v is empty", t.table)
+		}
+	}
+	if len(loaded) == 0 {
+		return fmt.Errorf("validation failed: nothing was loaded")
+	}
+
+	orphans := []struct {
+		description string
+		query       string
+	}{
+		{"ZIP+4 ranges with unknown counties", `SELECT COUNT(*) FROM zip4_ranges r LEFT JOIN counties c ON c.CountyFips = r.CountyFips WHERE c.CountyFips IS NULL`},
+		{"ZIPs with unknown counties", `SELECT COUNT(*) FROM zips z LEFT JOIN counties c ON c.CountyFips = z.CountyFips WHERE c.CountyFips IS NULL`},
+		{"counties with unknown states", `SELECT COUNT(*) FROM counties c LEFT JOIN states s ON s.StateAbbreviation = c.StateAbbreviation WHERE s.StateAbbreviation IS NULL`},
+	}
+	for _, o := range orphans {
+		var n int
+		if err := s.db.QueryRow(o.query).Scan(&n); err != nil {
+			return err
+		}
+		if n > 0 {
+			return fmt.Errorf("validation failed: %v %v", n, o.description)
+		}
+	}
+
+	zipsQuery := `SELECT ZipCode FROM zips ORDER BY ZipCode`
+	if !loaded[CityStateDataset] {
+		zipsQuery = `SELECT DISTINCT ZipCode FROM zip4_ranges ORDER BY ZipCode`
+	}
+	zips, err := sampleZips(s.db, zipsQuery, spotCheckZips)
+	if err != nil {
+		return err
+	}
+	for _, zip := range zips {
+		if loaded[CityStateDataset] {
+			var n int
+			err := queryRow(`SELECT COUNT(*) FROM zips z JOIN city_state_keys k ON k.ZipCode = z.ZipCode AND k.CityStateKey = z.PreferredLastLineCityStateKey WHERE z.ZipCode = ?`, zip).Scan(&n)
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				return fmt.Errorf("validation failed: ZIP %v has no record for its preferred city", zip)
+			}
+		}
+
+		if loaded[Zip4Dataset] {
+			var low sql.NullInt64
+			err := queryRow(`SELECT MIN(Plus4Low) FROM zip4_ranges WHERE ZipCode = ? AND Deliverable = 1`, zip).Scan(&low)
+			if err != nil {
+				return err
+			}
+			if !low.Valid {
+				continue
+			}
+			var n int
+			err = queryRow(`SELECT COUNT(*) FROM zip4_ranges WHERE ZipCode = ? AND Plus4Low <= ? AND Plus4High >= ?`, zip, low.Int64, low.Int64).Scan(&n)
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				return fmt.Errorf("validation failed: ZIP+4 %v-%04d not found", zip, low.Int64)
+			}
+		}
+	}
+
+	return nil
+}
+
+// sampleZips returns up to n ZIPs spread evenly through the query's results.
+func sampleZips(db *sql.DB, query string, n int) ([]string, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var all []string
+	for rows.Next() {
+		var zip string
+		if err := rows.Scan(&zip); err != nil {
+			return nil, err
+		}
+		all = append(all, zip)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(all) <= n {
+		return all, nil
+	}
+	sample := make([]string, n)
+	for i := range sample {
+		sample[i] = all[i*len(all)/n]
+	}
+	return sample, nil
+}
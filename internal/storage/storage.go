@@ -0,0 +1,475 @@
+// Package storage loads ZIP+4 and City State records into SQLite or
+// PostgreSQL databases with the schema described in schema.go.
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/corbaltcode/usps/citystate"
+	"github.com/corbaltcode/usps/zip4"
+	_ "github.com/jackc/pgx/v5/stdlib" // postgres driver
+	_ "github.com/mattn/go-sqlite3"    // sqlite driver
+)
+
+// Dataset names a kind of USPS data.
+type Dataset string
+
+const (
+	Zip4Dataset      Dataset = "zip4"
+	CityStateDataset Dataset = "citystate"
+)
+
+// Mode says what to do when a dataset is already loaded.
+type Mode string
+
+const (
+	// ModeReplace deletes the loaded records.
+	ModeReplace Mode = "replace"
+	// ModeAppend adds to the loaded records.
+	ModeAppend Mode = "append"
+	// ModeFail stops with an error.
+	ModeFail Mode = "fail"
+)
+
+// LoadMetadata describes one load or applied change file.
+type LoadMetadata struct {
+	Dataset         string
+	SourceFile      string
+	FulfillmentDate time.Time
+	// RecordCount is the number of records loaded.
+	RecordCount int
+	// ExpectedRecordCount is the number of records the file's header says
+	// it has, or 0 if that's unknown or the load skipped some records.
+	ExpectedRecordCount int
+	Release             string
+}
+
+// Changes are change (delta) files to apply. Either read function may be
+// nil.
+type Changes struct {
+	ReadZip4      func(yield func(zip4.Zip4Detail)) error
+	Zip4File      string
+	ReadCityState func(yield func(citystate.CityStateDetail)) error
+	CityStateFile string
+}
+
+// Store is a database that USPS data is loaded into.
+type Store interface {
+	// DB returns the underlying database.
+	DB() *sql.DB
+	Close() error
+
+	// Migrate brings the schema up to date.
+	Migrate() error
+	// TuneForBulkLoad trades durability for speed for the rest of the
+	// session. A failed load leaves a database that should be reloaded.
+	TuneForBulkLoad() error
+	// PrepareDataset empties a dataset or checks that it's empty,
+	// according to mode.
+	PrepareDataset(dataset Dataset, mode Mode) error
+	// LoadZip4 bulk loads the records read yields, committing every
+	// batchSize records, and returns the number loaded.
+	LoadZip4(read func(yield func(zip4.Zip4Detail)) error, batchSize int) (int, error)
+	// LoadCityState bulk loads the records read yields, committing every
+	// batchSize records, and returns the number loaded.
+	LoadCityState(read func(yield func(citystate.CityStateDetail)) error, batchSize int) (int, error)
+	// RecordLoad adds a row to load_metadata.
+	RecordLoad(m LoadMetadata) error
+	// CurrentRelease returns the release of the latest load or change file
+	// that recorded one.
+	CurrentRelease() (string, error)
+	// ApplyChanges applies change files to a database at baseRelease in
+	// one transaction, leaving it at release.
+	//
+	// ZIP+4 records are deleted and added by update key, in order. Since
+	// City State records have no action code or update key, each ZIP in
+	// the City State changes has its records replaced.
+	ApplyChanges(changes Changes, baseRelease, release string) error
+}
+
+// Open opens a store by DSN: postgres:// and postgresql:// URLs open
+// PostgreSQL; anything else is a SQLite file name, optionally prefixed by
+// "sqlite:".
+func Open(dsn string) (Store, error) {
+	if IsPostgres(dsn) {
+		db, err := sql.Open("pgx", dsn)
+		if err != nil {
+			return nil, err
+		}
+		return NewPostgres(db), nil
+	}
+
+	db, err := sql.Open("sqlite3", strings.TrimPrefix(dsn, "sqlite:"))
+	if err != nil {
+		return nil, err
+	}
+	return NewSQLite(db), nil
+}
+
+// IsPostgres reports whether a DSN names a PostgreSQL database.
+func IsPostgres(dsn string) bool {
+	return strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://")
+}
+
+// dialect holds what differs between databases.
+type dialect interface {
+	// rebind rewrites ? placeholders for the database.
+	rebind(query string) string
+	tableExists(q queryer, table string) (bool, error)
+	createTableQueries() []string
+	// migrateLegacy moves rows from tables that predate schema_version.
+	migrateLegacy(tx *sql.Tx) error
+	tune(db *sql.DB) error
+	bulkLoad(db *sql.DB, table string, columns []string, batchSize int, rows func(add func([]any) error) error) (int, error)
+	currentReleaseQuery() string
+}
+
+type sqlStore struct {
+	db *sql.DB
+	d  dialect
+}
+
+func (s *sqlStore) DB() *sql.DB {
+	return s.db
+}
+
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *sqlStore) Migrate() error {
+	return migrate(s.db, s.d)
+}
+
+func (s *sqlStore) TuneForBulkLoad() error {
+	return s.d.tune(s.db)
+}
+
+func (s *sqlStore) PrepareDataset(dataset Dataset, mode Mode) error {
+	var table string
+	var deleteQueries []string
+	switch dataset {
+	case Zip4Dataset:
+		table = "zip4_ranges"
+		deleteQueries = []string{`DELETE FROM zip4_ranges`}
+	case CityStateDataset:
+		table = "city_state_keys"
+		deleteQueries = []string{`DELETE FROM city_state_keys`, `DELETE FROM zips`}
+	default:
+		return fmt.Errorf("unknown dataset: %q", dataset)
+	}
+
+	var n int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM (SELECT 1 FROM ` + table + ` LIMIT 1) AS t`).Scan(&n); err != nil {
+		return err
+	}
+	if n == 0 {
+		return nil
+	}
+
+	switch mode {
+	case ModeFail:
+		return fmt.Errorf("%v is already loaded; use -mode replace or -mode append", dataset)
+	case ModeReplace:
+		for _, query := range deleteQueries {
+			if _, err := s.db.Exec(query); err != nil {
+				return err
+			}
+		}
+	case ModeAppend:
+	default:
+		return fmt.Errorf("unknown mode: %q", mode)
+	}
+	return nil
+}
+
+func (s *sqlStore) LoadZip4(read func(yield func(zip4.Zip4Detail)) error, batchSize int) (int, error) {
+	geo := newGeography()
+	n, err := s.bulkLoad("zip4_ranges", zip4RangeColumns, zip4RangeIndexes, batchSize, func(add func([]any) error) error {
+		var rowErr error
+		err := read(func(detail zip4.Zip4Detail) {
+			if rowErr != nil {
+				return
+			}
+			row, err := geo.zip4RangeRow(detail)
+			if err == nil {
+				err = add(row)
+			}
+			rowErr = err
+		})
+		if err != nil {
+			return err
+		}
+		return rowErr
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return n, s.saveGeography(geo)
+}
+
+func (s *sqlStore) LoadCityState(read func(yield func(citystate.CityStateDetail)) error, batchSize int) (int, error) {
+	geo := newGeography()
+	n, err := s.bulkLoad("city_state_keys", cityStateKeyColumns, cityStateKeyIndexes, batchSize, func(add func([]any) error) error {
+		var rowErr error
+		err := read(func(detail citystate.CityStateDetail) {
+			if rowErr != nil {
+				return
+			}
+			err := geo.addCityState(detail)
+			if err == nil {
+				err = add(cityStateKeyRow(detail))
+			}
+			rowErr = err
+		})
+		if err != nil {
+			return err
+		}
+		return rowErr
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return n, s.saveGeography(geo)
+}
+
+// bulkLoad drops a table's indexes, loads the rows passed to add and then
+// builds the indexes.
+func (s *sqlStore) bulkLoad(table string, columns []string, indexes map[string]string, batchSize int, rows func(add func([]any) error) error) (int, error) {
+	for name := range indexes {
+		if _, err := s.db.Exec(`DROP INDEX IF EXISTS ` + name); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := s.d.bulkLoad(s.db, table, columns, batchSize, rows)
+	if err != nil {
+		return 0, err
+	}
+
+	for name, query := range indexes {
+		start := time.Now()
+		if _, err := s.db.Exec(query); err != nil {
+			return 0, err
+		}
+		log.Printf("%v: built index %v in %v\n", table, name, time.Since(start).Round(time.Second))
+	}
+
+	return n, nil
+}
+
+func (s *sqlStore) saveGeography(geo *geography) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := geo.save(rebinder{tx, s.d}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *sqlStore) RecordLoad(m LoadMetadata) error {
+	return recordLoad(rebinder{s.db, s.d}, m)
+}
+
+func (s *sqlStore) CurrentRelease() (string, error) {
+	var release string
+	err := s.db.QueryRow(s.d.currentReleaseQuery()).Scan(&release)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("database has no recorded release; seed it with -release")
+	}
+	return release, err
+}
+
+func (s *sqlStore) ApplyChanges(changes Changes, baseRelease, release string) error {
+	current, err := s.CurrentRelease()
+	if err != nil {
+		return err
+	}
+	if current != baseRelease {
+		return fmt.Errorf("database is at release %q, not base release %q", current, baseRelease)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rtx := rebinder{tx, s.d}
+	geo := newGeography()
+
+	if changes.ReadZip4 != nil {
+		n, err := applyZip4Changes(tx, s.d, geo, changes.ReadZip4)
+		if err != nil {
+			return fmt.Errorf("error applying ZIP+4 changes: %v", err)
+		}
+		err = recordLoad(rtx, LoadMetadata{Dataset: "zip4-changes", SourceFile: changes.Zip4File, RecordCount: n, Release: release})
+		if err != nil {
+			return err
+		}
+	}
+
+	if changes.ReadCityState != nil {
+		n, err := applyCityStateChanges(tx, s.d, geo, changes.ReadCityState)
+		if err != nil {
+			return fmt.Errorf("error applying city state changes: %v", err)
+		}
+		err = recordLoad(rtx, LoadMetadata{Dataset: "citystate-changes", SourceFile: changes.CityStateFile, RecordCount: n, Release: release})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := geo.save(rtx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const zip4DeleteByUpdateKeyQuery = `DELETE FROM zip4_ranges WHERE UpdateKey = ?`
+const zip4CountByUpdateKeyQuery = `SELECT COUNT(*) FROM zip4_ranges WHERE UpdateKey = ?`
+const cityStateKeysDeleteByZipQuery = `DELETE FROM city_state_keys WHERE ZipCode = ?`
+
+func applyZip4Changes(tx *sql.Tx, d dialect, geo *geography, read func(yield func(zip4.Zip4Detail)) error) (int, error) {
+	insert, err := tx.Prepare(d.rebind(zip4RangeInsertQuery))
+	if err != nil {
+		return 0, err
+	}
+	defer insert.Close()
+
+	del, err := tx.Prepare(d.rebind(zip4DeleteByUpdateKeyQuery))
+	if err != nil {
+		return 0, err
+	}
+	defer del.Close()
+
+	count, err := tx.Prepare(d.rebind(zip4CountByUpdateKeyQuery))
+	if err != nil {
+		return 0, err
+	}
+	defer count.Close()
+
+	var added, deleted int
+	var applyErr error
+	err = read(func(detail zip4.Zip4Detail) {
+		if applyErr != nil {
+			return
+		}
+		applyErr = func() error {
+			switch detail.ActionCode {
+			case zip4.Zip4ActionCodeDelete:
+				res, err := del.Exec(detail.UpdateKey)
+				if err != nil {
+					return err
+				}
+				n, err := res.RowsAffected()
+				if err != nil {
+					return err
+				}
+				if n == 0 {
+					return fmt.Errorf("update key %v not found for delete", detail.UpdateKey)
+				}
+				deleted++
+			case zip4.Zip4ActionCodeAdd:
+				var n int
+				if err := count.QueryRow(detail.UpdateKey).Scan(&n); err != nil {
+					return err
+				}
+				if n > 0 {
+					return fmt.Errorf("update key %v already exists", detail.UpdateKey)
+				}
+				row, err := geo.zip4RangeRow(detail)
+				if err != nil {
+					return err
+				}
+				if _, err := insert.Exec(row...); err != nil {
+					return err
+				}
+				added++
+			default:
+				return fmt.Errorf("unknown action code %q for update key %v", detail.ActionCode, detail.UpdateKey)
+			}
+			return nil
+		}()
+	})
+	if err != nil {
+		return 0, err
+	}
+	if applyErr != nil {
+		return 0, applyErr
+	}
+
+	log.Printf("%v ZIP+4 records added, %v deleted.\n", added, deleted)
+	return added + deleted, nil
+}
+
+func applyCityStateChanges(tx *sql.Tx, d dialect, geo *geography, read func(yield func(citystate.CityStateDetail)) error) (int, error) {
+	var zips []string
+	byZip := make(map[string][]citystate.CityStateDetail)
+	err := read(func(detail citystate.CityStateDetail) {
+		if _, ok := byZip[detail.ZipCode]; !ok {
+			zips = append(zips, detail.ZipCode)
+		}
+		byZip[detail.ZipCode] = append(byZip[detail.ZipCode], detail)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	insert, err := tx.Prepare(d.rebind(cityStateKeyInsertQuery))
+	if err != nil {
+		return 0, err
+	}
+	defer insert.Close()
+
+	records := 0
+	for _, zip := range zips {
+		if _, err := tx.Exec(d.rebind(cityStateKeysDeleteByZipQuery), zip); err != nil {
+			return 0, err
+		}
+		for _, detail := range byZip[zip] {
+			if err := geo.addCityState(detail); err != nil {
+				return 0, err
+			}
+			if _, err := insert.Exec(cityStateKeyRow(detail)...); err != nil {
+				return 0, err
+			}
+			records++
+		}
+	}
+
+	log.Printf("City State records replaced for %v ZIPs.\n", len(zips))
+	return records, nil
+}
+
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+type queryer interface {
+	execer
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// rebinder rewrites placeholders before executing queries.
+type rebinder struct {
+	e execer
+	d dialect
+}
+
+func (r rebinder) Exec(query string, args ...any) (sql.Result, error) {
+	return r.e.Exec(r.d.rebind(query), args...)
+}
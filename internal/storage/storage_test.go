@@ -0,0 +1,341 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/corbaltcode/usps/citystate"
+	"github.com/corbaltcode/usps/zip4"
+)
+
+// Set USPS_TEST_POSTGRES_DSN to a scratch database to also test PostgreSQL.
+// The tests drop and recreate the tables in it.
+const postgresDSNEnv = "USPS_TEST_POSTGRES_DSN"
+
+// forEachDSN runs a test against a new SQLite file and, if configured, a
+// PostgreSQL database.
+func forEachDSN(t *testing.T, test func(t *testing.T, dsn string)) {
+	t.Run("sqlite", func(t *testing.T) {
+		test(t, filepath.Join(t.TempDir(), "test.db"))
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		dsn := os.Getenv(postgresDSNEnv)
+		if dsn == "" {
+			t.Skipf("%v not set", postgresDSNEnv)
+		}
+		test(t, dsn)
+	})
+}
+
+func forEachStore(t *testing.T, test func(t *testing.T, s Store)) {
+	t.Run("sqlite", func(t *testing.T) {
+		s, err := Open(filepath.Join(t.TempDir(), "test.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+		if err := s.Migrate(); err != nil {
+			t.Fatal(err)
+		}
+		test(t, s)
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		dsn := os.Getenv(postgresDSNEnv)
+		if dsn == "" {
+			t.Skipf("%v not set", postgresDSNEnv)
+		}
+		s, err := Open(dsn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+		for _, table := range []string{"zip4_ranges", "city_state_keys", "zips", "counties", "states", "load_metadata", "schema_version"} {
+			if _, err := s.DB().Exec(`DROP TABLE IF EXISTS ` + table); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := s.Migrate(); err != nil {
+			t.Fatal(err)
+		}
+		test(t, s)
+	})
+}
+
+func zip4Detail(updateKey, action, low, high string) zip4.Zip4Detail {
+	return zip4.Zip4Detail{
+		ZipCode:           "22201",
+		UpdateKey:         updateKey,
+		ActionCode:        action,
+		RecordTypeCode:    "S",
+		StateAbbreviation: "VA",
+		CountyNumber:      "013",
+		Plus4LowNumber:    zip4.Zip4Number(low),
+		Plus4HighNumber:   zip4.Zip4Number(high),
+	}
+}
+
+func cityStateDetail(key, name string) citystate.CityStateDetail {
+	return citystate.CityStateDetail{
+		ZipCode:                        "22201",
+		CityStateKey:                   key,
+		CityStateName:                  name,
+		PreferredLastLineCityStateKey:  "X12345",
+		PreferredLastLineCityStateName: "ARLINGTON",
+		ZipClassificationCode:          " ",
+		StateAbbreviation:              "VA",
+		CountyNumber:                   "013",
+		CountyName:                     "ARLINGTON",
+	}
+}
+
+func readZip4(details ...zip4.Zip4Detail) func(yield func(zip4.Zip4Detail)) error {
+	return func(yield func(zip4.Zip4Detail)) error {
+		for _, d := range details {
+			yield(d)
+		}
+		return nil
+	}
+}
+
+func readCityState(details ...citystate.CityStateDetail) func(yield func(citystate.CityStateDetail)) error {
+	return func(yield func(citystate.CityStateDetail)) error {
+		for _, d := range details {
+			yield(d)
+		}
+		return nil
+	}
+}
+
+func count(t *testing.T, s Store, query string) int {
+	t.Helper()
+	var n int
+	if err := s.DB().QueryRow(query).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func load(t *testing.T, s Store) {
+	t.Helper()
+	loadRelease(t, s, "2026-09")
+}
+
+func loadRelease(t *testing.T, s Store, release string) {
+	t.Helper()
+	n, err := s.LoadZip4(readZip4(
+		zip4Detail("0000000001", "A", "0001", "0010"),
+		zip4Detail("0000000002", "A", "0020", "0029"),
+		zip4Detail("0000000003", "A", "00ND", "00ND"),
+	), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("loaded %v zip4 records, want 3", n)
+	}
+
+	n, err = s.LoadCityState(readCityState(cityStateDetail("X12345", "ARLINGTON")), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("loaded %v city state records, want 1", n)
+	}
+
+	if err := s.RecordLoad(LoadMetadata{Dataset: string(CityStateDataset), SourceFile: "test.tar", RecordCount: 1, ExpectedRecordCount: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RecordLoad(LoadMetadata{Dataset: string(Zip4Dataset), SourceFile: "test.tar", RecordCount: 3, ExpectedRecordCount: 3, Release: release}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		load(t, s)
+
+		if n := count(t, s, `SELECT COUNT(*) FROM zip4_ranges WHERE Deliverable = 1`); n != 2 {
+			t.Errorf("got %v deliverable ranges, want 2", n)
+		}
+		if n := count(t, s, `SELECT COUNT(*) FROM counties WHERE CountyFips = '51013' AND CountyName = 'ARLINGTON'`); n != 1 {
+			t.Errorf("got %v Arlington counties, want 1", n)
+		}
+		if n := count(t, s, `SELECT COUNT(*) FROM zips WHERE ZipCode = '22201' AND CountyFips = '51013'`); n != 1 {
+			t.Errorf("got %v zips, want 1", n)
+		}
+
+		release, err := s.CurrentRelease()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if release != "2026-09" {
+			t.Errorf("got release %q, want 2026-09", release)
+		}
+	})
+}
+
+func TestPrepareDataset(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		load(t, s)
+
+		if err := s.PrepareDataset(Zip4Dataset, ModeFail); err == nil {
+			t.Error("ModeFail: expected an error for a loaded dataset")
+		}
+		if err := s.PrepareDataset(Zip4Dataset, ModeAppend); err != nil {
+			t.Errorf("ModeAppend: %v", err)
+		}
+		if n := count(t, s, `SELECT COUNT(*) FROM zip4_ranges`); n != 3 {
+			t.Errorf("ModeAppend: got %v ranges, want 3", n)
+		}
+		if err := s.PrepareDataset(Zip4Dataset, ModeReplace); err != nil {
+			t.Errorf("ModeReplace: %v", err)
+		}
+		if n := count(t, s, `SELECT COUNT(*) FROM zip4_ranges`); n != 0 {
+			t.Errorf("ModeReplace: got %v ranges, want 0", n)
+		}
+		if err := s.PrepareDataset(Zip4Dataset, ModeFail); err != nil {
+			t.Errorf("ModeFail: %v", err)
+		}
+	})
+}
+
+func TestApplyChanges(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		load(t, s)
+
+		changes := Changes{
+			ReadZip4: readZip4(
+				zip4Detail("0000000001", zip4.Zip4ActionCodeDelete, "0001", "0010"),
+				zip4Detail("0000000091", zip4.Zip4ActionCodeAdd, "0001", "0005"),
+				zip4Detail("0000000092", zip4.Zip4ActionCodeAdd, "0006", "0010"),
+			),
+			Zip4File:      "zip4.txt",
+			ReadCityState: readCityState(cityStateDetail("X12345", "ARLINGTON"), cityStateDetail("X23456", "CLARENDON")),
+			CityStateFile: "ctystate.txt",
+		}
+
+		err := s.ApplyChanges(changes, "2026-08", "2026-10")
+		if err == nil || !strings.Contains(err.Error(), "base release") {
+			t.Fatalf("wrong base release: got %v, want a base release error", err)
+		}
+
+		if err := s.ApplyChanges(changes, "2026-09", "2026-10"); err != nil {
+			t.Fatal(err)
+		}
+
+		if n := count(t, s, `SELECT COUNT(*) FROM zip4_ranges`); n != 4 {
+			t.Errorf("got %v ranges, want 4", n)
+		}
+		if n := count(t, s, `SELECT COUNT(*) FROM zip4_ranges WHERE UpdateKey = '0000000001'`); n != 0 {
+			t.Errorf("deleted range still present")
+		}
+		if n := count(t, s, `SELECT COUNT(*) FROM city_state_keys WHERE ZipCode = '22201'`); n != 2 {
+			t.Errorf("got %v city state keys, want 2", n)
+		}
+
+		release, err := s.CurrentRelease()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if release != "2026-10" {
+			t.Errorf("got release %q, want 2026-10", release)
+		}
+
+		// Deleting a key that's gone fails and leaves the database as is.
+		err = s.ApplyChanges(Changes{ReadZip4: readZip4(zip4Detail("0000000001", zip4.Zip4ActionCodeDelete, "0001", "0010"))}, "2026-10", "2026-11")
+		if err == nil {
+			t.Fatal("expected an error deleting a missing update key")
+		}
+		if release, _ := s.CurrentRelease(); release != "2026-10" {
+			t.Errorf("got release %q after failed apply, want 2026-10", release)
+		}
+	})
+}
+
+func stage(t *testing.T, dsn, release string) *Staging {
+	t.Helper()
+	s, err := OpenStaging(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	loadRelease(t, s, release)
+	return s
+}
+
+func liveRelease(t *testing.T, dsn string) string {
+	t.Helper()
+	s, err := Open(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	release, err := s.CurrentRelease()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return release
+}
+
+func TestStaging(t *testing.T) {
+	forEachDSN(t, func(t *testing.T, dsn string) {
+		for _, release := range []string{"2026-08", "2026-09", "2026-10"} {
+			s := stage(t, dsn, release)
+			if err := s.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Promote(1); err != nil {
+				t.Fatal(err)
+			}
+			if got := liveRelease(t, dsn); got != release {
+				t.Fatalf("after promoting %v, live release is %v", release, got)
+			}
+		}
+
+		if err := Rollback(dsn); err != nil {
+			t.Fatal(err)
+		}
+		if got := liveRelease(t, dsn); got != "2026-09" {
+			t.Errorf("after rollback, live release is %v, want 2026-09", got)
+		}
+		// Only one previous version was kept.
+		if err := Rollback(dsn); err == nil {
+			t.Error("expected an error rolling back with no previous version")
+		}
+	})
+}
+
+func TestStagingValidate(t *testing.T) {
+	forEachDSN(t, func(t *testing.T, dsn string) {
+		s, err := OpenStaging(dsn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+		if err := s.Migrate(); err != nil {
+			t.Fatal(err)
+		}
+
+		// A short read loads fewer records than the file header lists.
+		n, err := s.LoadZip4(readZip4(
+			zip4Detail("0000000001", "A", "0001", "0010"),
+			zip4Detail("0000000002", "A", "0020", "0029"),
+		), 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.RecordLoad(LoadMetadata{Dataset: string(Zip4Dataset), SourceFile: "test.tar", RecordCount: n, ExpectedRecordCount: 3}); err != nil {
+			t.Fatal(err)
+		}
+		err = s.Validate()
+		if err == nil || !strings.Contains(err.Error(), "3 records") {
+			t.Errorf("got %v, want a record count error", err)
+		}
+	})
+}
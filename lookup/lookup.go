@@ -0,0 +1,210 @@
+// Package lookup answers ZIP, ZIP+4, city and county questions from a
+// database created by seed-db or from records held in memory.
+//
+// Results are current records, so the zip4.Zip4Details returned have no
+// ActionCode.
+package lookup
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/corbaltcode/usps/citystate"
+	"github.com/corbaltcode/usps/zip4"
+)
+
+// ErrNotFound is returned for ZIPs and ZIP+4s that aren't in the data.
+var ErrNotFound = errors.New("not found")
+
+// InvalidError reports a malformed argument, such as a ZIP that isn't five
+// digits.
+type InvalidError struct {
+	What  string
+	Value string
+}
+
+func (e *InvalidError) Error() string {
+	return fmt.Sprintf("invalid %v: %q", e.What, e.Value)
+}
+
+var zipPattern = regexp.MustCompile(`^[0-9]{5}$`)
+var zip4Pattern = regexp.MustCompile(`^([0-9]{5})-?([0-9]{4})$`)
+
+// ZipInfo describes a ZIP from the City State data.
+type ZipInfo struct {
+	ZipCode               string `json:"zip_code"`
+	ZipClassificationCode string `json:"zip_classification_code"`
+	StateAbbreviation     string `json:"state_abbreviation"`
+	// County is the ZIP's county in the City State data. A ZIP can span
+	// counties; see Store.Counties.
+	County                         County `json:"county"`
+	PreferredLastLineCityStateKey  string `json:"preferred_last_line_city_state_key"`
+	PreferredLastLineCityStateName string `json:"preferred_last_line_city_state_name"`
+}
+
+// County is a county. Zip4Records is the number of a ZIP's ZIP+4 records in
+// the county when returned by Store.Counties.
+type County struct {
+	Fips              string `json:"fips"`
+	StateAbbreviation string `json:"state_abbreviation"`
+	CountyNumber      string `json:"county_number"`
+	Name              string `json:"name,omitempty"`
+	Zip4Records       int    `json:"zip4_records,omitempty"`
+}
+
+// City is a city name in a state.
+type City struct {
+	Name              string `json:"name"`
+	StateAbbreviation string `json:"state_abbreviation"`
+}
+
+type Store interface {
+	// Zip returns a ZIP's City State information.
+	Zip(zip string) (ZipInfo, error)
+	// Cities returns a ZIP's City State records, by city state key. They
+	// include its preferred, acceptable and unacceptable city names.
+	Cities(zip string) ([]citystate.CityStateDetail, error)
+	// ZipsForCity returns the sorted ZIPs with a city name in a state. The
+	// name is compared after citystate.NormalizeCityName, so "St. Louis"
+	// finds SAINT LOUIS.
+	ZipsForCity(state, city string) ([]string, error)
+	// ZipsForCounty returns the sorted ZIPs with ZIP+4 records in a county,
+	// or whose City State county it is, by five-digit FIPS code.
+	ZipsForCounty(fips string) ([]string, error)
+	// Counties returns the counties of a ZIP's ZIP+4 records, most records
+	// first, or its City State county if it has no ZIP+4 records.
+	Counties(zip string) ([]County, error)
+	// Zip4Ranges returns a ZIP's ZIP+4 records, by update key.
+	Zip4Ranges(zip string) ([]zip4.Zip4Detail, error)
+	// Zip4 returns the deliverable ZIP+4 record whose range contains a
+	// nine-digit code such as "222011234" or "22201-1234". If ranges
+	// overlap, the narrowest is returned.
+	Zip4(code string) (zip4.Zip4Detail, error)
+	// CompleteCity returns up to limit mailing city names starting with a
+	// prefix, sorted by name and state. The prefix is normalized by
+	// citystate.NormalizeCityPrefix, so "St" completes to both SAINT LOUIS
+	// and STAFFORD. An empty state matches all states; a limit of 0 returns
+	// all matches.
+	CompleteCity(state, prefix string, limit int) ([]City, error)
+	// SearchCities returns up to limit city names in a state that are
+	// similar to a misspelled one, most similar first, as found by a
+	// citystate.CityIndex of the store's City State records.
+	SearchCities(state, city string, limit int) ([]citystate.CityMatch, error)
+	// Release identifies the data, such as "2026-10", or is empty if it's
+	// unknown.
+	Release() (string, error)
+}
+
+// SplitZip4 splits a nine-digit code such as "222011234" or "22201-1234"
+// into the ZIP and add-on.
+func SplitZip4(code string) (string, zip4.Zip4Number, error) {
+	m := zip4Pattern.FindStringSubmatch(strings.TrimSpace(code))
+	if m == nil {
+		return "", "", &InvalidError{"ZIP+4", code}
+	}
+	return m[1], zip4.Zip4Number(m[2]), nil
+}
+
+func checkZip(zip string) error {
+	if !zipPattern.MatchString(zip) {
+		return &InvalidError{"ZIP", zip}
+	}
+	return nil
+}
+
+// narrowest returns the narrowest deliverable range containing plus4, or
+// false if none does.
+func narrowest(ranges []zip4.Zip4Detail, plus4 zip4.Zip4Number) (zip4.Zip4Detail, bool) {
+	n, err := plus4.Int()
+	if err != nil {
+		return zip4.Zip4Detail{}, false
+	}
+
+	var best zip4.Zip4Detail
+	bestWidth := -1
+	for _, r := range ranges {
+		low, err := r.Plus4LowNumber.Int()
+		if err != nil {
+			continue
+		}
+		high, err := r.Plus4HighNumber.Int()
+		if err != nil {
+			continue
+		}
+		if low <= n && n <= high && (bestWidth < 0 || high-low < bestWidth) {
+			best, bestWidth = r, high-low
+		}
+	}
+	return best, bestWidth >= 0
+}
+
+// completeCity returns up to limit cities completing any of a typed prefix's
+// normalized forms, sorted by name and state. complete returns up to limit
+// sorted completions of one form.
+func completeCity(prefix string, limit int, complete func(prefix string) ([]City, error)) ([]City, error) {
+	var cities []City
+	for _, p := range citystate.NormalizeCityPrefix(prefix) {
+		found, err := complete(p)
+		if err != nil {
+			return nil, err
+		}
+		cities = append(cities, found...)
+	}
+
+	sort.Slice(cities, func(i, j int) bool {
+		if cities[i].Name != cities[j].Name {
+			return cities[i].Name < cities[j].Name
+		}
+		return cities[i].StateAbbreviation < cities[j].StateAbbreviation
+	})
+	deduped := cities[:0]
+	for i, c := range cities {
+		if i == 0 || c != cities[i-1] {
+			deduped = append(deduped, c)
+		}
+	}
+	if limit > 0 && len(deduped) > limit {
+		deduped = deduped[:limit]
+	}
+	return deduped, nil
+}
+
+// sortCounties orders counties by ZIP+4 records, most first, then FIPS.
+func sortCounties(counties []County) {
+	sort.Slice(counties, func(i, j int) bool {
+		if counties[i].Zip4Records != counties[j].Zip4Records {
+			return counties[i].Zip4Records > counties[j].Zip4Records
+		}
+		return counties[i].Fips < counties[j].Fips
+	})
+}
+
+// CityStateRecords adapts a Store for a citystate.Validator. The records
+// implement citystate.CitySearcher with Store.SearchCities, so misspelled
+// cities get suggestions.
+func CityStateRecords(store Store) citystate.Records {
+	return cityStateRecords{store}
+}
+
+type cityStateRecords struct {
+	store Store
+}
+
+func (r cityStateRecords) ZipRecords(zip string) ([]citystate.CityStateDetail, error) {
+	records, err := r.store.Cities(zip)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return records, err
+}
+
+func (r cityStateRecords) ZipsForCity(state, city string) ([]string, error) {
+	return r.store.ZipsForCity(state, city)
+}
+
+func (r cityStateRecords) SearchCities(state, city string, limit int) ([]citystate.CityMatch, error) {
+	return r.store.SearchCities(state, city, limit)
+}
@@ -0,0 +1,310 @@
+package lookup
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/corbaltcode/usps/citystate"
+	"github.com/corbaltcode/usps/internal/storage"
+	"github.com/corbaltcode/usps/zip4"
+)
+
+var testZip4s = []zip4.Zip4Detail{
+	{ZipCode: "22201", UpdateKey: "0000000001", ActionCode: "A", RecordTypeCode: "S", StateAbbreviation: "VA", CountyNumber: "013", Plus4LowNumber: "0001", Plus4HighNumber: "0099"},
+	{ZipCode: "22201", UpdateKey: "0000000002", ActionCode: "A", RecordTypeCode: "F", StateAbbreviation: "VA", CountyNumber: "013", Plus4LowNumber: "0010", Plus4HighNumber: "0010"},
+	{ZipCode: "22201", UpdateKey: "0000000003", ActionCode: "A", RecordTypeCode: "S", StateAbbreviation: "VA", CountyNumber: "013", Plus4LowNumber: "01ND", Plus4HighNumber: "01ND"},
+	{ZipCode: "22201", UpdateKey: "0000000004", ActionCode: "A", RecordTypeCode: "S", StateAbbreviation: "VA", CountyNumber: "510", Plus4LowNumber: "0200", Plus4HighNumber: "0299"},
+	{ZipCode: "20001", UpdateKey: "0000000005", ActionCode: "A", RecordTypeCode: "S", StateAbbreviation: "DC", CountyNumber: "001", Plus4LowNumber: "0001", Plus4HighNumber: "0099"},
+}
+
+func cityState(zip, key, name, mailing, state, county, countyName string) citystate.CityStateDetail {
+	return citystate.CityStateDetail{
+		CopyrightDetailCode:            citystate.CityStateCopyrightDetailCodeDetail,
+		ZipCode:                        zip,
+		CityStateKey:                   key,
+		ZipClassificationCode:          " ",
+		CityStateName:                  name,
+		CityStateNameAbbreviation:      "             ",
+		CityStateNameFacilityCode:      "P",
+		CityStateMailingNameIndicator:  mailing,
+		PreferredLastLineCityStateKey:  "X00001",
+		PreferredLastLineCityStateName: "ARLINGTON",
+		CityDeliveryIndicator:          "Y",
+		CarrierRouteRateSortation:      "A",
+		UniqueZipNameIndicator:         "N",
+		FinanceNumber:                  "123456",
+		StateAbbreviation:              state,
+		CountyNumber:                   county,
+		CountyName:                     countyName,
+	}
+}
+
+var testCityStates = []citystate.CityStateDetail{
+	cityState("22201", "X00002", "CLARENDON", "Y", "VA", "013", "ARLINGTON"),
+	cityState("22201", "X00001", "ARLINGTON", "Y", "VA", "013", "ARLINGTON"),
+	cityState("22201", "X00003", "ARL", "N", "VA", "013", "ARLINGTON"),
+	cityState("22203", "X00001", "ARLINGTON", "Y", "VA", "013", "ARLINGTON"),
+	cityState("22204", "X00004", "ARLINGTON HEIGHTS", "Y", "VA", "013", "ARLINGTON"),
+	cityState("22554", "X00005", "STAFFORD", "Y", "VA", "179", "STAFFORD"),
+	cityState("63101", "X00006", "SAINT LOUIS", "Y", "MO", "510", "SAINT LOUIS CITY"),
+}
+
+func readZip4(yield func(zip4.Zip4Detail)) error {
+	for _, d := range testZip4s {
+		yield(d)
+	}
+	return nil
+}
+
+func readCityState(yield func(citystate.CityStateDetail)) error {
+	for _, d := range testCityStates {
+		yield(d)
+	}
+	return nil
+}
+
+func forEachStore(t *testing.T, test func(t *testing.T, s Store)) {
+	t.Run("sqlite", func(t *testing.T) {
+		st, err := storage.Open(filepath.Join(t.TempDir(), "test.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer st.Close()
+		if err := st.Migrate(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := st.LoadZip4(readZip4, 100); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := st.LoadCityState(readCityState, 100); err != nil {
+			t.Fatal(err)
+		}
+		test(t, NewSQLiteStore(st.DB()))
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		s, err := NewMemoryStore(readZip4, readCityState)
+		if err != nil {
+			t.Fatal(err)
+		}
+		test(t, s)
+	})
+}
+
+func TestZip(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		info, err := s.Zip("22201")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := ZipInfo{
+			ZipCode:                        "22201",
+			ZipClassificationCode:          " ",
+			StateAbbreviation:              "VA",
+			County:                         County{Fips: "51013", StateAbbreviation: "VA", CountyNumber: "013", Name: "ARLINGTON"},
+			PreferredLastLineCityStateKey:  "X00001",
+			PreferredLastLineCityStateName: "ARLINGTON",
+		}
+		if info != want {
+			t.Errorf("got %+v, want %+v", info, want)
+		}
+
+		if _, err := s.Zip("99999"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("unknown ZIP: got %v, want ErrNotFound", err)
+		}
+		if _, err := s.Zip("2220"); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("invalid ZIP: got %v, want an invalid ZIP error", err)
+		}
+	})
+}
+
+func TestCities(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		cities, err := s.Cities("22201")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []citystate.CityStateDetail{testCityStates[1], testCityStates[0], testCityStates[2]}
+		if !reflect.DeepEqual(cities, want) {
+			t.Errorf("got %+v, want %+v", cities, want)
+		}
+	})
+}
+
+func TestZipsForCity(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		zips, err := s.ZipsForCity("VA", " arlington ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"22201", "22203"}; !reflect.DeepEqual(zips, want) {
+			t.Errorf("got %v, want %v", zips, want)
+		}
+
+		zips, err = s.ZipsForCity("MO", "St. Louis")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"63101"}; !reflect.DeepEqual(zips, want) {
+			t.Errorf("got %v, want %v", zips, want)
+		}
+	})
+}
+
+func TestCounties(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		counties, err := s.Counties("22201")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []County{
+			{Fips: "51013", StateAbbreviation: "VA", CountyNumber: "013", Name: "ARLINGTON", Zip4Records: 3},
+			{Fips: "51510", StateAbbreviation: "VA", CountyNumber: "510", Zip4Records: 1},
+		}
+		if !reflect.DeepEqual(counties, want) {
+			t.Errorf("got %+v, want %+v", counties, want)
+		}
+
+		// 22203 has no ZIP+4 records, so its City State county is used.
+		counties, err = s.Counties("22203")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want = []County{{Fips: "51013", StateAbbreviation: "VA", CountyNumber: "013", Name: "ARLINGTON"}}
+		if !reflect.DeepEqual(counties, want) {
+			t.Errorf("got %+v, want %+v", counties, want)
+		}
+	})
+}
+
+func TestZip4(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		d, err := s.Zip4("22201-0010")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := testZip4s[1]
+		want.ActionCode = ""
+		if d != want {
+			t.Errorf("got %+v, want the narrowest range %+v", d, want)
+		}
+
+		d, err = s.Zip4("222010050")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.UpdateKey != "0000000001" {
+			t.Errorf("got %+v, want update key 0000000001", d)
+		}
+
+		if _, err := s.Zip4("22201-0150"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("got %v, want ErrNotFound", err)
+		}
+	})
+}
+
+func TestCompleteCity(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		cities, err := s.CompleteCity("VA", "arl", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []City{{"ARLINGTON", "VA"}, {"ARLINGTON HEIGHTS", "VA"}}
+		if !reflect.DeepEqual(cities, want) {
+			t.Errorf("got %v, want %v", cities, want)
+		}
+
+		cities, err = s.CompleteCity("", "", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []City{{"ARLINGTON", "VA"}}; !reflect.DeepEqual(cities, want) {
+			t.Errorf("got %v, want %v", cities, want)
+		}
+
+		// "St" may be Saint or the start of a name.
+		cities, err = s.CompleteCity("", "St", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []City{{"SAINT LOUIS", "MO"}, {"STAFFORD", "VA"}}; !reflect.DeepEqual(cities, want) {
+			t.Errorf("got %v, want %v", cities, want)
+		}
+
+		cities, err = s.CompleteCity("", "st", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []City{{"SAINT LOUIS", "MO"}}; !reflect.DeepEqual(cities, want) {
+			t.Errorf("got %v, want %v", cities, want)
+		}
+
+		cities, err = s.CompleteCity("MO", "St. L", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []City{{"SAINT LOUIS", "MO"}}; !reflect.DeepEqual(cities, want) {
+			t.Errorf("got %v, want %v", cities, want)
+		}
+	})
+}
+
+func TestSearchCities(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		matches, err := s.SearchCities("VA", "Arlingtn", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(matches) != 1 || matches[0].Name != "ARLINGTON" || !reflect.DeepEqual(matches[0].Zips, []string{"22201", "22203"}) {
+			t.Errorf("got %+v", matches)
+		}
+
+		// The adapter suggests misspelled cities to a Validator.
+		val, err := citystate.NewValidator(CityStateRecords(s)).Validate("Stafferd", "VA", "22554")
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, sug := range val.Suggestions {
+			found = found || sug == citystate.Suggestion{City: "STAFFORD", State: "VA", Zip: "22554"}
+		}
+		if !found {
+			t.Errorf("suggestions: got %v, want STAFFORD VA 22554", val.Suggestions)
+		}
+	})
+}
+
+func TestZipsForCounty(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		zips, err := s.ZipsForCounty("51013")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"22201", "22203", "22204"}; !reflect.DeepEqual(zips, want) {
+			t.Errorf("got %v, want %v", zips, want)
+		}
+	})
+}
+
+func TestZip4Ranges(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		ranges, err := s.Zip4Ranges("22201")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var want []zip4.Zip4Detail
+		for _, d := range testZip4s[:4] {
+			d.ActionCode = ""
+			want = append(want, d)
+		}
+		if !reflect.DeepEqual(ranges, want) {
+			t.Errorf("got %+v, want %+v", ranges, want)
+		}
+
+		if _, err := s.Zip4Ranges("22203"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("got %v, want ErrNotFound", err)
+		}
+	})
+}
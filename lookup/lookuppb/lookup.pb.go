@@ -0,0 +1,1523 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.1
+// 	protoc        (unknown)
+// source: lookup.proto
+
+// Package usps.lookup.v1 serves ZIP, ZIP+4, city and county lookups from
+// USPS City State and ZIP+4 data. Messages mirror the Go types in the
+// citystate, zip4, lookup and ziptocounty packages.
+
+package lookuppb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// CityStateDetail mirrors citystate.CityStateDetail.
+type CityStateDetail struct {
+	state                          protoimpl.MessageState `protogen:"open.v1"`
+	CopyrightDetailCode            string                 `protobuf:"bytes,1,opt,name=copyright_detail_code,json=copyrightDetailCode,proto3" json:"copyright_detail_code,omitempty"`
+	ZipCode                        string                 `protobuf:"bytes,2,opt,name=zip_code,json=zipCode,proto3" json:"zip_code,omitempty"`
+	CityStateKey                   string                 `protobuf:"bytes,3,opt,name=city_state_key,json=cityStateKey,proto3" json:"city_state_key,omitempty"`
+	ZipClassificationCode          string                 `protobuf:"bytes,4,opt,name=zip_classification_code,json=zipClassificationCode,proto3" json:"zip_classification_code,omitempty"`
+	CityStateName                  string                 `protobuf:"bytes,5,opt,name=city_state_name,json=cityStateName,proto3" json:"city_state_name,omitempty"`
+	CityStateNameAbbreviation      string                 `protobuf:"bytes,6,opt,name=city_state_name_abbreviation,json=cityStateNameAbbreviation,proto3" json:"city_state_name_abbreviation,omitempty"`
+	CityStateNameFacilityCode      string                 `protobuf:"bytes,7,opt,name=city_state_name_facility_code,json=cityStateNameFacilityCode,proto3" json:"city_state_name_facility_code,omitempty"`
+	CityStateMailingNameIndicator  string                 `protobuf:"bytes,8,opt,name=city_state_mailing_name_indicator,json=cityStateMailingNameIndicator,proto3" json:"city_state_mailing_name_indicator,omitempty"`
+	PreferredLastLineCityStateKey  string                 `protobuf:"bytes,9,opt,name=preferred_last_line_city_state_key,json=preferredLastLineCityStateKey,proto3" json:"preferred_last_line_city_state_key,omitempty"`
+	PreferredLastLineCityStateName string                 `protobuf:"bytes,10,opt,name=preferred_last_line_city_state_name,json=preferredLastLineCityStateName,proto3" json:"preferred_last_line_city_state_name,omitempty"`
+	CityDeliveryIndicator          string                 `protobuf:"bytes,11,opt,name=city_delivery_indicator,json=cityDeliveryIndicator,proto3" json:"city_delivery_indicator,omitempty"`
+	CarrierRouteRateSortation      string                 `protobuf:"bytes,12,opt,name=carrier_route_rate_sortation,json=carrierRouteRateSortation,proto3" json:"carrier_route_rate_sortation,omitempty"`
+	UniqueZipNameIndicator         string                 `protobuf:"bytes,13,opt,name=unique_zip_name_indicator,json=uniqueZipNameIndicator,proto3" json:"unique_zip_name_indicator,omitempty"`
+	FinanceNumber                  string                 `protobuf:"bytes,14,opt,name=finance_number,json=financeNumber,proto3" json:"finance_number,omitempty"`
+	StateAbbreviation              string                 `protobuf:"bytes,15,opt,name=state_abbreviation,json=stateAbbreviation,proto3" json:"state_abbreviation,omitempty"`
+	CountyNumber                   string                 `protobuf:"bytes,16,opt,name=county_number,json=countyNumber,proto3" json:"county_number,omitempty"`
+	CountyName                     string                 `protobuf:"bytes,17,opt,name=county_name,json=countyName,proto3" json:"county_name,omitempty"`
+	unknownFields                  protoimpl.UnknownFields
+	sizeCache                      protoimpl.SizeCache
+}
+
+func (x *CityStateDetail) Reset() {
+	*x = CityStateDetail{}
+	mi := &file_lookup_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CityStateDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CityStateDetail) ProtoMessage() {}
+
+func (x *CityStateDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_lookup_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CityStateDetail.ProtoReflect.Descriptor instead.
+func (*CityStateDetail) Descriptor() ([]byte, []int) {
+	return file_lookup_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CityStateDetail) GetCopyrightDetailCode() string {
+	if x != nil {
+		return x.CopyrightDetailCode
+	}
+	return ""
+}
+
+func (x *CityStateDetail) GetZipCode() string {
+	if x != nil {
+		return x.ZipCode
+	}
+	return ""
+}
+
+func (x *CityStateDetail) GetCityStateKey() string {
+	if x != nil {
+		return x.CityStateKey
+	}
+	return ""
+}
+
+func (x *CityStateDetail) GetZipClassificationCode() string {
+	if x != nil {
+		return x.ZipClassificationCode
+	}
+	return ""
+}
+
+func (x *CityStateDetail) GetCityStateName() string {
+	if x != nil {
+		return x.CityStateName
+	}
+	return ""
+}
+
+func (x *CityStateDetail) GetCityStateNameAbbreviation() string {
+	if x != nil {
+		return x.CityStateNameAbbreviation
+	}
+	return ""
+}
+
+func (x *CityStateDetail) GetCityStateNameFacilityCode() string {
+	if x != nil {
+		return x.CityStateNameFacilityCode
+	}
+	return ""
+}
+
+func (x *CityStateDetail) GetCityStateMailingNameIndicator() string {
+	if x != nil {
+		return x.CityStateMailingNameIndicator
+	}
+	return ""
+}
+
+func (x *CityStateDetail) GetPreferredLastLineCityStateKey() string {
+	if x != nil {
+		return x.PreferredLastLineCityStateKey
+	}
+	return ""
+}
+
+func (x *CityStateDetail) GetPreferredLastLineCityStateName() string {
+	if x != nil {
+		return x.PreferredLastLineCityStateName
+	}
+	return ""
+}
+
+func (x *CityStateDetail) GetCityDeliveryIndicator() string {
+	if x != nil {
+		return x.CityDeliveryIndicator
+	}
+	return ""
+}
+
+func (x *CityStateDetail) GetCarrierRouteRateSortation() string {
+	if x != nil {
+		return x.CarrierRouteRateSortation
+	}
+	return ""
+}
+
+func (x *CityStateDetail) GetUniqueZipNameIndicator() string {
+	if x != nil {
+		return x.UniqueZipNameIndicator
+	}
+	return ""
+}
+
+func (x *CityStateDetail) GetFinanceNumber() string {
+	if x != nil {
+		return x.FinanceNumber
+	}
+	return ""
+}
+
+func (x *CityStateDetail) GetStateAbbreviation() string {
+	if x != nil {
+		return x.StateAbbreviation
+	}
+	return ""
+}
+
+func (x *CityStateDetail) GetCountyNumber() string {
+	if x != nil {
+		return x.CountyNumber
+	}
+	return ""
+}
+
+func (x *CityStateDetail) GetCountyName() string {
+	if x != nil {
+		return x.CountyName
+	}
+	return ""
+}
+
+// Zip4Detail mirrors zip4.Zip4Detail. Add-ons are four characters; a
+// non-deliverable add-on is its sector followed by "ND".
+type Zip4Detail struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	ZipCode           string                 `protobuf:"bytes,1,opt,name=zip_code,json=zipCode,proto3" json:"zip_code,omitempty"`
+	UpdateKey         string                 `protobuf:"bytes,2,opt,name=update_key,json=updateKey,proto3" json:"update_key,omitempty"`
+	ActionCode        string                 `protobuf:"bytes,3,opt,name=action_code,json=actionCode,proto3" json:"action_code,omitempty"`
+	RecordTypeCode    string                 `protobuf:"bytes,4,opt,name=record_type_code,json=recordTypeCode,proto3" json:"record_type_code,omitempty"`
+	StateAbbreviation string                 `protobuf:"bytes,5,opt,name=state_abbreviation,json=stateAbbreviation,proto3" json:"state_abbreviation,omitempty"`
+	CountyNumber      string                 `protobuf:"bytes,6,opt,name=county_number,json=countyNumber,proto3" json:"county_number,omitempty"`
+	Plus4LowNumber    string                 `protobuf:"bytes,7,opt,name=plus4_low_number,json=plus4LowNumber,proto3" json:"plus4_low_number,omitempty"`
+	Plus4HighNumber   string                 `protobuf:"bytes,8,opt,name=plus4_high_number,json=plus4HighNumber,proto3" json:"plus4_high_number,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Zip4Detail) Reset() {
+	*x = Zip4Detail{}
+	mi := &file_lookup_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Zip4Detail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Zip4Detail) ProtoMessage() {}
+
+func (x *Zip4Detail) ProtoReflect() protoreflect.Message {
+	mi := &file_lookup_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Zip4Detail.ProtoReflect.Descriptor instead.
+func (*Zip4Detail) Descriptor() ([]byte, []int) {
+	return file_lookup_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Zip4Detail) GetZipCode() string {
+	if x != nil {
+		return x.ZipCode
+	}
+	return ""
+}
+
+func (x *Zip4Detail) GetUpdateKey() string {
+	if x != nil {
+		return x.UpdateKey
+	}
+	return ""
+}
+
+func (x *Zip4Detail) GetActionCode() string {
+	if x != nil {
+		return x.ActionCode
+	}
+	return ""
+}
+
+func (x *Zip4Detail) GetRecordTypeCode() string {
+	if x != nil {
+		return x.RecordTypeCode
+	}
+	return ""
+}
+
+func (x *Zip4Detail) GetStateAbbreviation() string {
+	if x != nil {
+		return x.StateAbbreviation
+	}
+	return ""
+}
+
+func (x *Zip4Detail) GetCountyNumber() string {
+	if x != nil {
+		return x.CountyNumber
+	}
+	return ""
+}
+
+func (x *Zip4Detail) GetPlus4LowNumber() string {
+	if x != nil {
+		return x.Plus4LowNumber
+	}
+	return ""
+}
+
+func (x *Zip4Detail) GetPlus4HighNumber() string {
+	if x != nil {
+		return x.Plus4HighNumber
+	}
+	return ""
+}
+
+// County mirrors lookup.County.
+type County struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Fips              string                 `protobuf:"bytes,1,opt,name=fips,proto3" json:"fips,omitempty"`
+	StateAbbreviation string                 `protobuf:"bytes,2,opt,name=state_abbreviation,json=stateAbbreviation,proto3" json:"state_abbreviation,omitempty"`
+	CountyNumber      string                 `protobuf:"bytes,3,opt,name=county_number,json=countyNumber,proto3" json:"county_number,omitempty"`
+	Name              string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Zip4Records       int32                  `protobuf:"varint,5,opt,name=zip4_records,json=zip4Records,proto3" json:"zip4_records,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *County) Reset() {
+	*x = County{}
+	mi := &file_lookup_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *County) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*County) ProtoMessage() {}
+
+func (x *County) ProtoReflect() protoreflect.Message {
+	mi := &file_lookup_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use County.ProtoReflect.Descriptor instead.
+func (*County) Descriptor() ([]byte, []int) {
+	return file_lookup_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *County) GetFips() string {
+	if x != nil {
+		return x.Fips
+	}
+	return ""
+}
+
+func (x *County) GetStateAbbreviation() string {
+	if x != nil {
+		return x.StateAbbreviation
+	}
+	return ""
+}
+
+func (x *County) GetCountyNumber() string {
+	if x != nil {
+		return x.CountyNumber
+	}
+	return ""
+}
+
+func (x *County) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *County) GetZip4Records() int32 {
+	if x != nil {
+		return x.Zip4Records
+	}
+	return 0
+}
+
+// ZipInfo mirrors lookup.ZipInfo.
+type ZipInfo struct {
+	state                          protoimpl.MessageState `protogen:"open.v1"`
+	ZipCode                        string                 `protobuf:"bytes,1,opt,name=zip_code,json=zipCode,proto3" json:"zip_code,omitempty"`
+	ZipClassificationCode          string                 `protobuf:"bytes,2,opt,name=zip_classification_code,json=zipClassificationCode,proto3" json:"zip_classification_code,omitempty"`
+	StateAbbreviation              string                 `protobuf:"bytes,3,opt,name=state_abbreviation,json=stateAbbreviation,proto3" json:"state_abbreviation,omitempty"`
+	County                         *County                `protobuf:"bytes,4,opt,name=county,proto3" json:"county,omitempty"`
+	PreferredLastLineCityStateKey  string                 `protobuf:"bytes,5,opt,name=preferred_last_line_city_state_key,json=preferredLastLineCityStateKey,proto3" json:"preferred_last_line_city_state_key,omitempty"`
+	PreferredLastLineCityStateName string                 `protobuf:"bytes,6,opt,name=preferred_last_line_city_state_name,json=preferredLastLineCityStateName,proto3" json:"preferred_last_line_city_state_name,omitempty"`
+	unknownFields                  protoimpl.UnknownFields
+	sizeCache                      protoimpl.SizeCache
+}
+
+func (x *ZipInfo) Reset() {
+	*x = ZipInfo{}
+	mi := &file_lookup_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZipInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZipInfo) ProtoMessage() {}
+
+func (x *ZipInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_lookup_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZipInfo.ProtoReflect.Descriptor instead.
+func (*ZipInfo) Descriptor() ([]byte, []int) {
+	return file_lookup_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ZipInfo) GetZipCode() string {
+	if x != nil {
+		return x.ZipCode
+	}
+	return ""
+}
+
+func (x *ZipInfo) GetZipClassificationCode() string {
+	if x != nil {
+		return x.ZipClassificationCode
+	}
+	return ""
+}
+
+func (x *ZipInfo) GetStateAbbreviation() string {
+	if x != nil {
+		return x.StateAbbreviation
+	}
+	return ""
+}
+
+func (x *ZipInfo) GetCounty() *County {
+	if x != nil {
+		return x.County
+	}
+	return nil
+}
+
+func (x *ZipInfo) GetPreferredLastLineCityStateKey() string {
+	if x != nil {
+		return x.PreferredLastLineCityStateKey
+	}
+	return ""
+}
+
+func (x *ZipInfo) GetPreferredLastLineCityStateName() string {
+	if x != nil {
+		return x.PreferredLastLineCityStateName
+	}
+	return ""
+}
+
+// CrosswalkEntry mirrors ziptocounty.CrosswalkEntry: one ZIP-county pair
+// weighted by the share of the ZIP's add-ons in the county, like the HUD
+// USPS crosswalk.
+type CrosswalkEntry struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ZipCode          string                 `protobuf:"bytes,1,opt,name=zip_code,json=zipCode,proto3" json:"zip_code,omitempty"`
+	CountyFips       string                 `protobuf:"bytes,2,opt,name=county_fips,json=countyFips,proto3" json:"county_fips,omitempty"`
+	AddOnCount       int32                  `protobuf:"varint,3,opt,name=add_on_count,json=addOnCount,proto3" json:"add_on_count,omitempty"`
+	ResidentialRatio float64                `protobuf:"fixed64,4,opt,name=residential_ratio,json=residentialRatio,proto3" json:"residential_ratio,omitempty"`
+	BusinessRatio    float64                `protobuf:"fixed64,5,opt,name=business_ratio,json=businessRatio,proto3" json:"business_ratio,omitempty"`
+	OtherRatio       float64                `protobuf:"fixed64,6,opt,name=other_ratio,json=otherRatio,proto3" json:"other_ratio,omitempty"`
+	TotalRatio       float64                `protobuf:"fixed64,7,opt,name=total_ratio,json=totalRatio,proto3" json:"total_ratio,omitempty"`
+	Primary          bool                   `protobuf:"varint,8,opt,name=primary,proto3" json:"primary,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CrosswalkEntry) Reset() {
+	*x = CrosswalkEntry{}
+	mi := &file_lookup_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CrosswalkEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrosswalkEntry) ProtoMessage() {}
+
+func (x *CrosswalkEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_lookup_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrosswalkEntry.ProtoReflect.Descriptor instead.
+func (*CrosswalkEntry) Descriptor() ([]byte, []int) {
+	return file_lookup_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CrosswalkEntry) GetZipCode() string {
+	if x != nil {
+		return x.ZipCode
+	}
+	return ""
+}
+
+func (x *CrosswalkEntry) GetCountyFips() string {
+	if x != nil {
+		return x.CountyFips
+	}
+	return ""
+}
+
+func (x *CrosswalkEntry) GetAddOnCount() int32 {
+	if x != nil {
+		return x.AddOnCount
+	}
+	return 0
+}
+
+func (x *CrosswalkEntry) GetResidentialRatio() float64 {
+	if x != nil {
+		return x.ResidentialRatio
+	}
+	return 0
+}
+
+func (x *CrosswalkEntry) GetBusinessRatio() float64 {
+	if x != nil {
+		return x.BusinessRatio
+	}
+	return 0
+}
+
+func (x *CrosswalkEntry) GetOtherRatio() float64 {
+	if x != nil {
+		return x.OtherRatio
+	}
+	return 0
+}
+
+func (x *CrosswalkEntry) GetTotalRatio() float64 {
+	if x != nil {
+		return x.TotalRatio
+	}
+	return 0
+}
+
+func (x *CrosswalkEntry) GetPrimary() bool {
+	if x != nil {
+		return x.Primary
+	}
+	return false
+}
+
+type City struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Name              string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	StateAbbreviation string                 `protobuf:"bytes,2,opt,name=state_abbreviation,json=stateAbbreviation,proto3" json:"state_abbreviation,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *City) Reset() {
+	*x = City{}
+	mi := &file_lookup_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *City) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*City) ProtoMessage() {}
+
+func (x *City) ProtoReflect() protoreflect.Message {
+	mi := &file_lookup_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use City.ProtoReflect.Descriptor instead.
+func (*City) Descriptor() ([]byte, []int) {
+	return file_lookup_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *City) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *City) GetStateAbbreviation() string {
+	if x != nil {
+		return x.StateAbbreviation
+	}
+	return ""
+}
+
+type GetZipRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Zip           string                 `protobuf:"bytes,1,opt,name=zip,proto3" json:"zip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetZipRequest) Reset() {
+	*x = GetZipRequest{}
+	mi := &file_lookup_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetZipRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetZipRequest) ProtoMessage() {}
+
+func (x *GetZipRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lookup_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetZipRequest.ProtoReflect.Descriptor instead.
+func (*GetZipRequest) Descriptor() ([]byte, []int) {
+	return file_lookup_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetZipRequest) GetZip() string {
+	if x != nil {
+		return x.Zip
+	}
+	return ""
+}
+
+type GetZipResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Zip           *ZipInfo               `protobuf:"bytes,1,opt,name=zip,proto3" json:"zip,omitempty"`
+	Cities        []*CityStateDetail     `protobuf:"bytes,2,rep,name=cities,proto3" json:"cities,omitempty"`
+	Counties      []*County              `protobuf:"bytes,3,rep,name=counties,proto3" json:"counties,omitempty"`
+	Crosswalk     []*CrosswalkEntry      `protobuf:"bytes,4,rep,name=crosswalk,proto3" json:"crosswalk,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetZipResponse) Reset() {
+	*x = GetZipResponse{}
+	mi := &file_lookup_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetZipResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetZipResponse) ProtoMessage() {}
+
+func (x *GetZipResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lookup_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetZipResponse.ProtoReflect.Descriptor instead.
+func (*GetZipResponse) Descriptor() ([]byte, []int) {
+	return file_lookup_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetZipResponse) GetZip() *ZipInfo {
+	if x != nil {
+		return x.Zip
+	}
+	return nil
+}
+
+func (x *GetZipResponse) GetCities() []*CityStateDetail {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+func (x *GetZipResponse) GetCounties() []*County {
+	if x != nil {
+		return x.Counties
+	}
+	return nil
+}
+
+func (x *GetZipResponse) GetCrosswalk() []*CrosswalkEntry {
+	if x != nil {
+		return x.Crosswalk
+	}
+	return nil
+}
+
+type GetZip4Request struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// code is nine digits, with or without a hyphen.
+	Code          string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetZip4Request) Reset() {
+	*x = GetZip4Request{}
+	mi := &file_lookup_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetZip4Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetZip4Request) ProtoMessage() {}
+
+func (x *GetZip4Request) ProtoReflect() protoreflect.Message {
+	mi := &file_lookup_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetZip4Request.ProtoReflect.Descriptor instead.
+func (*GetZip4Request) Descriptor() ([]byte, []int) {
+	return file_lookup_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetZip4Request) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type GetZipsForCityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetZipsForCityRequest) Reset() {
+	*x = GetZipsForCityRequest{}
+	mi := &file_lookup_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetZipsForCityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetZipsForCityRequest) ProtoMessage() {}
+
+func (x *GetZipsForCityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lookup_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetZipsForCityRequest.ProtoReflect.Descriptor instead.
+func (*GetZipsForCityRequest) Descriptor() ([]byte, []int) {
+	return file_lookup_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetZipsForCityRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *GetZipsForCityRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetZipsForCountyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Fips          string                 `protobuf:"bytes,1,opt,name=fips,proto3" json:"fips,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetZipsForCountyRequest) Reset() {
+	*x = GetZipsForCountyRequest{}
+	mi := &file_lookup_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetZipsForCountyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetZipsForCountyRequest) ProtoMessage() {}
+
+func (x *GetZipsForCountyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lookup_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetZipsForCountyRequest.ProtoReflect.Descriptor instead.
+func (*GetZipsForCountyRequest) Descriptor() ([]byte, []int) {
+	return file_lookup_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetZipsForCountyRequest) GetFips() string {
+	if x != nil {
+		return x.Fips
+	}
+	return ""
+}
+
+type ZipList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Zips          []string               `protobuf:"bytes,1,rep,name=zips,proto3" json:"zips,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZipList) Reset() {
+	*x = ZipList{}
+	mi := &file_lookup_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZipList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZipList) ProtoMessage() {}
+
+func (x *ZipList) ProtoReflect() protoreflect.Message {
+	mi := &file_lookup_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZipList.ProtoReflect.Descriptor instead.
+func (*ZipList) Descriptor() ([]byte, []int) {
+	return file_lookup_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ZipList) GetZips() []string {
+	if x != nil {
+		return x.Zips
+	}
+	return nil
+}
+
+type CompleteCityRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// state is optional.
+	State  string `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Prefix string `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// limit of 0 returns all matches.
+	Limit         int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteCityRequest) Reset() {
+	*x = CompleteCityRequest{}
+	mi := &file_lookup_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteCityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteCityRequest) ProtoMessage() {}
+
+func (x *CompleteCityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lookup_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteCityRequest.ProtoReflect.Descriptor instead.
+func (*CompleteCityRequest) Descriptor() ([]byte, []int) {
+	return file_lookup_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CompleteCityRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *CompleteCityRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *CompleteCityRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type CompleteCityResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cities        []*City                `protobuf:"bytes,1,rep,name=cities,proto3" json:"cities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteCityResponse) Reset() {
+	*x = CompleteCityResponse{}
+	mi := &file_lookup_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteCityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteCityResponse) ProtoMessage() {}
+
+func (x *CompleteCityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lookup_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteCityResponse.ProtoReflect.Descriptor instead.
+func (*CompleteCityResponse) Descriptor() ([]byte, []int) {
+	return file_lookup_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CompleteCityResponse) GetCities() []*City {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+type GetCrosswalkRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Zip   string                 `protobuf:"bytes,1,opt,name=zip,proto3" json:"zip,omitempty"`
+	// deliverable_only excludes non-deliverable add-ons.
+	DeliverableOnly bool `protobuf:"varint,2,opt,name=deliverable_only,json=deliverableOnly,proto3" json:"deliverable_only,omitempty"`
+	// record_types restricts the crosswalk to record type codes; all are
+	// included if empty.
+	RecordTypes   []string `protobuf:"bytes,3,rep,name=record_types,json=recordTypes,proto3" json:"record_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCrosswalkRequest) Reset() {
+	*x = GetCrosswalkRequest{}
+	mi := &file_lookup_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCrosswalkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCrosswalkRequest) ProtoMessage() {}
+
+func (x *GetCrosswalkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lookup_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCrosswalkRequest.ProtoReflect.Descriptor instead.
+func (*GetCrosswalkRequest) Descriptor() ([]byte, []int) {
+	return file_lookup_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetCrosswalkRequest) GetZip() string {
+	if x != nil {
+		return x.Zip
+	}
+	return ""
+}
+
+func (x *GetCrosswalkRequest) GetDeliverableOnly() bool {
+	if x != nil {
+		return x.DeliverableOnly
+	}
+	return false
+}
+
+func (x *GetCrosswalkRequest) GetRecordTypes() []string {
+	if x != nil {
+		return x.RecordTypes
+	}
+	return nil
+}
+
+type GetCrosswalkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*CrosswalkEntry      `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCrosswalkResponse) Reset() {
+	*x = GetCrosswalkResponse{}
+	mi := &file_lookup_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCrosswalkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCrosswalkResponse) ProtoMessage() {}
+
+func (x *GetCrosswalkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lookup_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCrosswalkResponse.ProtoReflect.Descriptor instead.
+func (*GetCrosswalkResponse) Descriptor() ([]byte, []int) {
+	return file_lookup_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetCrosswalkResponse) GetEntries() []*CrosswalkEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// ZipResult is one ZIP's result in a stream. Failures of one ZIP don't end
+// the stream.
+type ZipResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Zip           string                 `protobuf:"bytes,1,opt,name=zip,proto3" json:"zip,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Response      *GetZipResponse        `protobuf:"bytes,4,opt,name=response,proto3" json:"response,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZipResult) Reset() {
+	*x = ZipResult{}
+	mi := &file_lookup_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZipResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZipResult) ProtoMessage() {}
+
+func (x *ZipResult) ProtoReflect() protoreflect.Message {
+	mi := &file_lookup_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZipResult.ProtoReflect.Descriptor instead.
+func (*ZipResult) Descriptor() ([]byte, []int) {
+	return file_lookup_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ZipResult) GetZip() string {
+	if x != nil {
+		return x.Zip
+	}
+	return ""
+}
+
+func (x *ZipResult) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *ZipResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ZipResult) GetResponse() *GetZipResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+var File_lookup_proto protoreflect.FileDescriptor
+
+var file_lookup_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0e,
+	0x75, 0x73, 0x70, 0x73, 0x2e, 0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x22, 0x9b,
+	0x07, 0x0a, 0x0f, 0x43, 0x69, 0x74, 0x79, 0x53, 0x74, 0x61, 0x74, 0x65, 0x44, 0x65, 0x74, 0x61,
+	0x69, 0x6c, 0x12, 0x32, 0x0a, 0x15, 0x63, 0x6f, 0x70, 0x79, 0x72, 0x69, 0x67, 0x68, 0x74, 0x5f,
+	0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x13, 0x63, 0x6f, 0x70, 0x79, 0x72, 0x69, 0x67, 0x68, 0x74, 0x44, 0x65, 0x74, 0x61,
+	0x69, 0x6c, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x7a, 0x69, 0x70, 0x5f, 0x63, 0x6f,
+	0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x7a, 0x69, 0x70, 0x43, 0x6f, 0x64,
+	0x65, 0x12, 0x24, 0x0a, 0x0e, 0x63, 0x69, 0x74, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f,
+	0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x69, 0x74, 0x79, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x12, 0x36, 0x0a, 0x17, 0x7a, 0x69, 0x70, 0x5f, 0x63,
+	0x6c, 0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x63, 0x6f,
+	0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x15, 0x7a, 0x69, 0x70, 0x43, 0x6c, 0x61,
+	0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x12,
+	0x26, 0x0a, 0x0f, 0x63, 0x69, 0x74, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x69, 0x74, 0x79, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x3f, 0x0a, 0x1c, 0x63, 0x69, 0x74, 0x79, 0x5f,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x61, 0x62, 0x62, 0x72, 0x65,
+	0x76, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x19, 0x63,
+	0x69, 0x74, 0x79, 0x53, 0x74, 0x61, 0x74, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x41, 0x62, 0x62, 0x72,
+	0x65, 0x76, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x40, 0x0a, 0x1d, 0x63, 0x69, 0x74, 0x79,
+	0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x66, 0x61, 0x63, 0x69,
+	0x6c, 0x69, 0x74, 0x79, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x19, 0x63, 0x69, 0x74, 0x79, 0x53, 0x74, 0x61, 0x74, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x46, 0x61,
+	0x63, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x48, 0x0a, 0x21, 0x63, 0x69,
+	0x74, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x6d, 0x61, 0x69, 0x6c, 0x69, 0x6e, 0x67,
+	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x69, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x6f, 0x72, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x1d, 0x63, 0x69, 0x74, 0x79, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x4d, 0x61, 0x69, 0x6c, 0x69, 0x6e, 0x67, 0x4e, 0x61, 0x6d, 0x65, 0x49, 0x6e, 0x64, 0x69, 0x63,
+	0x61, 0x74, 0x6f, 0x72, 0x12, 0x49, 0x0a, 0x22, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65,
+	0x64, 0x5f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x63, 0x69, 0x74, 0x79,
+	0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x1d, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x4c, 0x61, 0x73, 0x74, 0x4c,
+	0x69, 0x6e, 0x65, 0x43, 0x69, 0x74, 0x79, 0x53, 0x74, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x12,
+	0x4b, 0x0a, 0x23, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x5f, 0x6c, 0x61, 0x73,
+	0x74, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x63, 0x69, 0x74, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x1e, 0x70, 0x72,
+	0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x4c, 0x61, 0x73, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x43,
+	0x69, 0x74, 0x79, 0x53, 0x74, 0x61, 0x74, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x36, 0x0a, 0x17,
+	0x63, 0x69, 0x74, 0x79, 0x5f, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x5f, 0x69, 0x6e,
+	0x64, 0x69, 0x63, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x15, 0x63,
+	0x69, 0x74, 0x79, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x49, 0x6e, 0x64, 0x69, 0x63,
+	0x61, 0x74, 0x6f, 0x72, 0x12, 0x3f, 0x0a, 0x1c, 0x63, 0x61, 0x72, 0x72, 0x69, 0x65, 0x72, 0x5f,
+	0x72, 0x6f, 0x75, 0x74, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x73, 0x6f, 0x72, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x19, 0x63, 0x61, 0x72, 0x72,
+	0x69, 0x65, 0x72, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x61, 0x74, 0x65, 0x53, 0x6f, 0x72, 0x74,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x39, 0x0a, 0x19, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x5f,
+	0x7a, 0x69, 0x70, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x69, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74,
+	0x6f, 0x72, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65,
+	0x5a, 0x69, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x6f, 0x72,
+	0x12, 0x25, 0x0a, 0x0e, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x6e, 0x75, 0x6d, 0x62,
+	0x65, 0x72, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63,
+	0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x2d, 0x0a, 0x12, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x5f, 0x61, 0x62, 0x62, 0x72, 0x65, 0x76, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0f, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x11, 0x73, 0x74, 0x61, 0x74, 0x65, 0x41, 0x62, 0x62, 0x72, 0x65, 0x76,
+	0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x79,
+	0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x79, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0xbb, 0x02, 0x0a,
+	0x0a, 0x5a, 0x69, 0x70, 0x34, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x12, 0x19, 0x0a, 0x08, 0x7a,
+	0x69, 0x70, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x7a,
+	0x69, 0x70, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x4b, 0x65, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x5f, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0e, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x54, 0x79, 0x70, 0x65, 0x43, 0x6f, 0x64, 0x65,
+	0x12, 0x2d, 0x0a, 0x12, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x61, 0x62, 0x62, 0x72, 0x65, 0x76,
+	0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x41, 0x62, 0x62, 0x72, 0x65, 0x76, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x23, 0x0a, 0x0d, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x4e, 0x75,
+	0x6d, 0x62, 0x65, 0x72, 0x12, 0x28, 0x0a, 0x10, 0x70, 0x6c, 0x75, 0x73, 0x34, 0x5f, 0x6c, 0x6f,
+	0x77, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x70, 0x6c, 0x75, 0x73, 0x34, 0x4c, 0x6f, 0x77, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x2a,
+	0x0a, 0x11, 0x70, 0x6c, 0x75, 0x73, 0x34, 0x5f, 0x68, 0x69, 0x67, 0x68, 0x5f, 0x6e, 0x75, 0x6d,
+	0x62, 0x65, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x6c, 0x75, 0x73, 0x34,
+	0x48, 0x69, 0x67, 0x68, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0xa7, 0x01, 0x0a, 0x06, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69, 0x70, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69, 0x70, 0x73, 0x12, 0x2d, 0x0a, 0x12, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x5f, 0x61, 0x62, 0x62, 0x72, 0x65, 0x76, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x73, 0x74, 0x61, 0x74, 0x65, 0x41, 0x62, 0x62, 0x72,
+	0x65, 0x76, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x79, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x21, 0x0a, 0x0c, 0x7a, 0x69, 0x70, 0x34, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x7a, 0x69, 0x70, 0x34, 0x52, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x73, 0x22, 0xd3, 0x02, 0x0a, 0x07, 0x5a, 0x69, 0x70, 0x49, 0x6e, 0x66, 0x6f,
+	0x12, 0x19, 0x0a, 0x08, 0x7a, 0x69, 0x70, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x7a, 0x69, 0x70, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x36, 0x0a, 0x17, 0x7a,
+	0x69, 0x70, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x15, 0x7a, 0x69,
+	0x70, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43,
+	0x6f, 0x64, 0x65, 0x12, 0x2d, 0x0a, 0x12, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x61, 0x62, 0x62,
+	0x72, 0x65, 0x76, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x11, 0x73, 0x74, 0x61, 0x74, 0x65, 0x41, 0x62, 0x62, 0x72, 0x65, 0x76, 0x69, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x75, 0x73, 0x70, 0x73, 0x2e, 0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70,
+	0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x52, 0x06, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x79, 0x12, 0x49, 0x0a, 0x22, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x5f,
+	0x6c, 0x61, 0x73, 0x74, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x63, 0x69, 0x74, 0x79, 0x5f, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x1d,
+	0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x4c, 0x61, 0x73, 0x74, 0x4c, 0x69, 0x6e,
+	0x65, 0x43, 0x69, 0x74, 0x79, 0x53, 0x74, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x12, 0x4b, 0x0a,
+	0x23, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x5f, 0x6c, 0x61, 0x73, 0x74, 0x5f,
+	0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x63, 0x69, 0x74, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x1e, 0x70, 0x72, 0x65, 0x66,
+	0x65, 0x72, 0x72, 0x65, 0x64, 0x4c, 0x61, 0x73, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x43, 0x69, 0x74,
+	0x79, 0x53, 0x74, 0x61, 0x74, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x9e, 0x02, 0x0a, 0x0e, 0x43,
+	0x72, 0x6f, 0x73, 0x73, 0x77, 0x61, 0x6c, 0x6b, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x19, 0x0a,
+	0x08, 0x7a, 0x69, 0x70, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x7a, 0x69, 0x70, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x79, 0x5f, 0x66, 0x69, 0x70, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x79, 0x46, 0x69, 0x70, 0x73, 0x12, 0x20, 0x0a, 0x0c, 0x61, 0x64, 0x64,
+	0x5f, 0x6f, 0x6e, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0a, 0x61, 0x64, 0x64, 0x4f, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2b, 0x0a, 0x11, 0x72,
+	0x65, 0x73, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x10, 0x72, 0x65, 0x73, 0x69, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x69, 0x6f, 0x12, 0x25, 0x0a, 0x0e, 0x62, 0x75, 0x73, 0x69,
+	0x6e, 0x65, 0x73, 0x73, 0x5f, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x0d, 0x62, 0x75, 0x73, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x52, 0x61, 0x74, 0x69, 0x6f, 0x12,
+	0x1f, 0x0a, 0x0b, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x5f, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x52, 0x61, 0x74, 0x69, 0x6f,
+	0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x69,
+	0x6f, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x22, 0x49, 0x0a, 0x04, 0x43,
+	0x69, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2d, 0x0a, 0x12, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x5f, 0x61, 0x62, 0x62, 0x72, 0x65, 0x76, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x11, 0x73, 0x74, 0x61, 0x74, 0x65, 0x41, 0x62, 0x62, 0x72, 0x65, 0x76,
+	0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x21, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x5a, 0x69, 0x70,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x7a, 0x69, 0x70, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x7a, 0x69, 0x70, 0x22, 0xe6, 0x01, 0x0a, 0x0e, 0x47, 0x65,
+	0x74, 0x5a, 0x69, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x03,
+	0x7a, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x75, 0x73, 0x70, 0x73,
+	0x2e, 0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x5a, 0x69, 0x70, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x03, 0x7a, 0x69, 0x70, 0x12, 0x37, 0x0a, 0x06, 0x63, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x75, 0x73, 0x70, 0x73, 0x2e, 0x6c,
+	0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x69, 0x74, 0x79, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x52, 0x06, 0x63, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x12, 0x32, 0x0a, 0x08, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x75, 0x73, 0x70, 0x73, 0x2e, 0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70,
+	0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x69, 0x65, 0x73, 0x12, 0x3c, 0x0a, 0x09, 0x63, 0x72, 0x6f, 0x73, 0x73, 0x77, 0x61, 0x6c,
+	0x6b, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x75, 0x73, 0x70, 0x73, 0x2e, 0x6c,
+	0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x6f, 0x73, 0x73, 0x77, 0x61,
+	0x6c, 0x6b, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x63, 0x72, 0x6f, 0x73, 0x73, 0x77, 0x61,
+	0x6c, 0x6b, 0x22, 0x24, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x5a, 0x69, 0x70, 0x34, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x22, 0x41, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x5a,
+	0x69, 0x70, 0x73, 0x46, 0x6f, 0x72, 0x43, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x2d, 0x0a, 0x17, 0x47,
+	0x65, 0x74, 0x5a, 0x69, 0x70, 0x73, 0x46, 0x6f, 0x72, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69, 0x70, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69, 0x70, 0x73, 0x22, 0x1d, 0x0a, 0x07, 0x5a, 0x69,
+	0x70, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x7a, 0x69, 0x70, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x04, 0x7a, 0x69, 0x70, 0x73, 0x22, 0x59, 0x0a, 0x13, 0x43, 0x6f, 0x6d,
+	0x70, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x14,
+	0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x22, 0x44, 0x0a, 0x14, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65,
+	0x43, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x06,
+	0x63, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x75,
+	0x73, 0x70, 0x73, 0x2e, 0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x69,
+	0x74, 0x79, 0x52, 0x06, 0x63, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0x75, 0x0a, 0x13, 0x47, 0x65,
+	0x74, 0x43, 0x72, 0x6f, 0x73, 0x73, 0x77, 0x61, 0x6c, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x10, 0x0a, 0x03, 0x7a, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x7a, 0x69, 0x70, 0x12, 0x29, 0x0a, 0x10, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x61, 0x62,
+	0x6c, 0x65, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x64,
+	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x61, 0x62, 0x6c, 0x65, 0x4f, 0x6e, 0x6c, 0x79, 0x12, 0x21,
+	0x0a, 0x0c, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x54, 0x79, 0x70, 0x65,
+	0x73, 0x22, 0x50, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x43, 0x72, 0x6f, 0x73, 0x73, 0x77, 0x61, 0x6c,
+	0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x07, 0x65, 0x6e, 0x74,
+	0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x75, 0x73, 0x70,
+	0x73, 0x2e, 0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x6f, 0x73,
+	0x73, 0x77, 0x61, 0x6c, 0x6b, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72,
+	0x69, 0x65, 0x73, 0x22, 0x85, 0x01, 0x0a, 0x09, 0x5a, 0x69, 0x70, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x12, 0x10, 0x0a, 0x03, 0x7a, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x7a, 0x69, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x05, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12,
+	0x3a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1e, 0x2e, 0x75, 0x73, 0x70, 0x73, 0x2e, 0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x5a, 0x69, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xc9, 0x04, 0x0a, 0x0d,
+	0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x47, 0x0a,
+	0x06, 0x47, 0x65, 0x74, 0x5a, 0x69, 0x70, 0x12, 0x1d, 0x2e, 0x75, 0x73, 0x70, 0x73, 0x2e, 0x6c,
+	0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x5a, 0x69, 0x70, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x75, 0x73, 0x70, 0x73, 0x2e, 0x6c, 0x6f,
+	0x6f, 0x6b, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x5a, 0x69, 0x70, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x5a, 0x69, 0x70,
+	0x34, 0x12, 0x1e, 0x2e, 0x75, 0x73, 0x70, 0x73, 0x2e, 0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x5a, 0x69, 0x70, 0x34, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1a, 0x2e, 0x75, 0x73, 0x70, 0x73, 0x2e, 0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x2e,
+	0x76, 0x31, 0x2e, 0x5a, 0x69, 0x70, 0x34, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x12, 0x50, 0x0a,
+	0x0e, 0x47, 0x65, 0x74, 0x5a, 0x69, 0x70, 0x73, 0x46, 0x6f, 0x72, 0x43, 0x69, 0x74, 0x79, 0x12,
+	0x25, 0x2e, 0x75, 0x73, 0x70, 0x73, 0x2e, 0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x2e, 0x76, 0x31,
+	0x2e, 0x47, 0x65, 0x74, 0x5a, 0x69, 0x70, 0x73, 0x46, 0x6f, 0x72, 0x43, 0x69, 0x74, 0x79, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x75, 0x73, 0x70, 0x73, 0x2e, 0x6c, 0x6f,
+	0x6f, 0x6b, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x5a, 0x69, 0x70, 0x4c, 0x69, 0x73, 0x74, 0x12,
+	0x54, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x5a, 0x69, 0x70, 0x73, 0x46, 0x6f, 0x72, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x79, 0x12, 0x27, 0x2e, 0x75, 0x73, 0x70, 0x73, 0x2e, 0x6c, 0x6f, 0x6f, 0x6b, 0x75,
+	0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x5a, 0x69, 0x70, 0x73, 0x46, 0x6f, 0x72, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x75,
+	0x73, 0x70, 0x73, 0x2e, 0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x5a, 0x69,
+	0x70, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x59, 0x0a, 0x0c, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74,
+	0x65, 0x43, 0x69, 0x74, 0x79, 0x12, 0x23, 0x2e, 0x75, 0x73, 0x70, 0x73, 0x2e, 0x6c, 0x6f, 0x6f,
+	0x6b, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x43,
+	0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x75, 0x73, 0x70,
+	0x73, 0x2e, 0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70,
+	0x6c, 0x65, 0x74, 0x65, 0x43, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x59, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x43, 0x72, 0x6f, 0x73, 0x73, 0x77, 0x61, 0x6c, 0x6b,
+	0x12, 0x23, 0x2e, 0x75, 0x73, 0x70, 0x73, 0x2e, 0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x72, 0x6f, 0x73, 0x73, 0x77, 0x61, 0x6c, 0x6b, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x75, 0x73, 0x70, 0x73, 0x2e, 0x6c, 0x6f, 0x6f,
+	0x6b, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x72, 0x6f, 0x73, 0x73, 0x77,
+	0x61, 0x6c, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x0a, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x5a, 0x69, 0x70, 0x73, 0x12, 0x1d, 0x2e, 0x75, 0x73, 0x70, 0x73,
+	0x2e, 0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x5a, 0x69,
+	0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x75, 0x73, 0x70, 0x73, 0x2e,
+	0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x5a, 0x69, 0x70, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x28, 0x01, 0x30, 0x01, 0x42, 0x2d, 0x5a, 0x2b, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6f, 0x72, 0x62, 0x61, 0x6c, 0x74, 0x63, 0x6f, 0x64,
+	0x65, 0x2f, 0x75, 0x73, 0x70, 0x73, 0x2f, 0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x2f, 0x6c, 0x6f,
+	0x6f, 0x6b, 0x75, 0x70, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_lookup_proto_rawDescOnce sync.Once
+	file_lookup_proto_rawDescData = file_lookup_proto_rawDesc
+)
+
+func file_lookup_proto_rawDescGZIP() []byte {
+	file_lookup_proto_rawDescOnce.Do(func() {
+		file_lookup_proto_rawDescData = protoimpl.X.CompressGZIP(file_lookup_proto_rawDescData)
+	})
+	return file_lookup_proto_rawDescData
+}
+
+var file_lookup_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_lookup_proto_goTypes = []any{
+	(*CityStateDetail)(nil),         // 0: usps.lookup.v1.CityStateDetail
+	(*Zip4Detail)(nil),              // 1: usps.lookup.v1.Zip4Detail
+	(*County)(nil),                  // 2: usps.lookup.v1.County
+	(*ZipInfo)(nil),                 // 3: usps.lookup.v1.ZipInfo
+	(*CrosswalkEntry)(nil),          // 4: usps.lookup.v1.CrosswalkEntry
+	(*City)(nil),                    // 5: usps.lookup.v1.City
+	(*GetZipRequest)(nil),           // 6: usps.lookup.v1.GetZipRequest
+	(*GetZipResponse)(nil),          // 7: usps.lookup.v1.GetZipResponse
+	(*GetZip4Request)(nil),          // 8: usps.lookup.v1.GetZip4Request
+	(*GetZipsForCityRequest)(nil),   // 9: usps.lookup.v1.GetZipsForCityRequest
+	(*GetZipsForCountyRequest)(nil), // 10: usps.lookup.v1.GetZipsForCountyRequest
+	(*ZipList)(nil),                 // 11: usps.lookup.v1.ZipList
+	(*CompleteCityRequest)(nil),     // 12: usps.lookup.v1.CompleteCityRequest
+	(*CompleteCityResponse)(nil),    // 13: usps.lookup.v1.CompleteCityResponse
+	(*GetCrosswalkRequest)(nil),     // 14: usps.lookup.v1.GetCrosswalkRequest
+	(*GetCrosswalkResponse)(nil),    // 15: usps.lookup.v1.GetCrosswalkResponse
+	(*ZipResult)(nil),               // 16: usps.lookup.v1.ZipResult
+}
+var file_lookup_proto_depIdxs = []int32{
+	2,  // 0: usps.lookup.v1.ZipInfo.county:type_name -> usps.lookup.v1.County
+	3,  // 1: usps.lookup.v1.GetZipResponse.zip:type_name -> usps.lookup.v1.ZipInfo
+	0,  // 2: usps.lookup.v1.GetZipResponse.cities:type_name -> usps.lookup.v1.CityStateDetail
+	2,  // 3: usps.lookup.v1.GetZipResponse.counties:type_name -> usps.lookup.v1.County
+	4,  // 4: usps.lookup.v1.GetZipResponse.crosswalk:type_name -> usps.lookup.v1.CrosswalkEntry
+	5,  // 5: usps.lookup.v1.CompleteCityResponse.cities:type_name -> usps.lookup.v1.City
+	4,  // 6: usps.lookup.v1.GetCrosswalkResponse.entries:type_name -> usps.lookup.v1.CrosswalkEntry
+	7,  // 7: usps.lookup.v1.ZipResult.response:type_name -> usps.lookup.v1.GetZipResponse
+	6,  // 8: usps.lookup.v1.LookupService.GetZip:input_type -> usps.lookup.v1.GetZipRequest
+	8,  // 9: usps.lookup.v1.LookupService.GetZip4:input_type -> usps.lookup.v1.GetZip4Request
+	9,  // 10: usps.lookup.v1.LookupService.GetZipsForCity:input_type -> usps.lookup.v1.GetZipsForCityRequest
+	10, // 11: usps.lookup.v1.LookupService.GetZipsForCounty:input_type -> usps.lookup.v1.GetZipsForCountyRequest
+	12, // 12: usps.lookup.v1.LookupService.CompleteCity:input_type -> usps.lookup.v1.CompleteCityRequest
+	14, // 13: usps.lookup.v1.LookupService.GetCrosswalk:input_type -> usps.lookup.v1.GetCrosswalkRequest
+	6,  // 14: usps.lookup.v1.LookupService.StreamZips:input_type -> usps.lookup.v1.GetZipRequest
+	7,  // 15: usps.lookup.v1.LookupService.GetZip:output_type -> usps.lookup.v1.GetZipResponse
+	1,  // 16: usps.lookup.v1.LookupService.GetZip4:output_type -> usps.lookup.v1.Zip4Detail
+	11, // 17: usps.lookup.v1.LookupService.GetZipsForCity:output_type -> usps.lookup.v1.ZipList
+	11, // 18: usps.lookup.v1.LookupService.GetZipsForCounty:output_type -> usps.lookup.v1.ZipList
+	13, // 19: usps.lookup.v1.LookupService.CompleteCity:output_type -> usps.lookup.v1.CompleteCityResponse
+	15, // 20: usps.lookup.v1.LookupService.GetCrosswalk:output_type -> usps.lookup.v1.GetCrosswalkResponse
+	16, // 21: usps.lookup.v1.LookupService.StreamZips:output_type -> usps.lookup.v1.ZipResult
+	15, // [15:22] is the sub-list for method output_type
+	8,  // [8:15] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_lookup_proto_init() }
+func file_lookup_proto_init() {
+	if File_lookup_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_lookup_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_lookup_proto_goTypes,
+		DependencyIndexes: file_lookup_proto_depIdxs,
+		MessageInfos:      file_lookup_proto_msgTypes,
+	}.Build()
+	File_lookup_proto = out.File
+	file_lookup_proto_rawDesc = nil
+	file_lookup_proto_goTypes = nil
+	file_lookup_proto_depIdxs = nil
+}
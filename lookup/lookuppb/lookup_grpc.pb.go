@@ -0,0 +1,356 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: lookup.proto
+
+// Package usps.lookup.v1 serves ZIP, ZIP+4, city and county lookups from
+// USPS City State and ZIP+4 data. Messages mirror the Go types in the
+// citystate, zip4, lookup and ziptocounty packages.
+
+package lookuppb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	LookupService_GetZip_FullMethodName           = "/usps.lookup.v1.LookupService/GetZip"
+	LookupService_GetZip4_FullMethodName          = "/usps.lookup.v1.LookupService/GetZip4"
+	LookupService_GetZipsForCity_FullMethodName   = "/usps.lookup.v1.LookupService/GetZipsForCity"
+	LookupService_GetZipsForCounty_FullMethodName = "/usps.lookup.v1.LookupService/GetZipsForCounty"
+	LookupService_CompleteCity_FullMethodName     = "/usps.lookup.v1.LookupService/CompleteCity"
+	LookupService_GetCrosswalk_FullMethodName     = "/usps.lookup.v1.LookupService/GetCrosswalk"
+	LookupService_StreamZips_FullMethodName       = "/usps.lookup.v1.LookupService/StreamZips"
+)
+
+// LookupServiceClient is the client API for LookupService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// LookupService answers lookups from one data release, named in the
+// "usps-release" response header.
+type LookupServiceClient interface {
+	GetZip(ctx context.Context, in *GetZipRequest, opts ...grpc.CallOption) (*GetZipResponse, error)
+	GetZip4(ctx context.Context, in *GetZip4Request, opts ...grpc.CallOption) (*Zip4Detail, error)
+	GetZipsForCity(ctx context.Context, in *GetZipsForCityRequest, opts ...grpc.CallOption) (*ZipList, error)
+	GetZipsForCounty(ctx context.Context, in *GetZipsForCountyRequest, opts ...grpc.CallOption) (*ZipList, error)
+	CompleteCity(ctx context.Context, in *CompleteCityRequest, opts ...grpc.CallOption) (*CompleteCityResponse, error)
+	GetCrosswalk(ctx context.Context, in *GetCrosswalkRequest, opts ...grpc.CallOption) (*GetCrosswalkResponse, error)
+	// StreamZips looks up ZIPs as they're sent, returning results in order.
+	StreamZips(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[GetZipRequest, ZipResult], error)
+}
+
+type lookupServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLookupServiceClient(cc grpc.ClientConnInterface) LookupServiceClient {
+	return &lookupServiceClient{cc}
+}
+
+func (c *lookupServiceClient) GetZip(ctx context.Context, in *GetZipRequest, opts ...grpc.CallOption) (*GetZipResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetZipResponse)
+	err := c.cc.Invoke(ctx, LookupService_GetZip_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lookupServiceClient) GetZip4(ctx context.Context, in *GetZip4Request, opts ...grpc.CallOption) (*Zip4Detail, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Zip4Detail)
+	err := c.cc.Invoke(ctx, LookupService_GetZip4_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lookupServiceClient) GetZipsForCity(ctx context.Context, in *GetZipsForCityRequest, opts ...grpc.CallOption) (*ZipList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ZipList)
+	err := c.cc.Invoke(ctx, LookupService_GetZipsForCity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lookupServiceClient) GetZipsForCounty(ctx context.Context, in *GetZipsForCountyRequest, opts ...grpc.CallOption) (*ZipList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ZipList)
+	err := c.cc.Invoke(ctx, LookupService_GetZipsForCounty_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lookupServiceClient) CompleteCity(ctx context.Context, in *CompleteCityRequest, opts ...grpc.CallOption) (*CompleteCityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CompleteCityResponse)
+	err := c.cc.Invoke(ctx, LookupService_CompleteCity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lookupServiceClient) GetCrosswalk(ctx context.Context, in *GetCrosswalkRequest, opts ...grpc.CallOption) (*GetCrosswalkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCrosswalkResponse)
+	err := c.cc.Invoke(ctx, LookupService_GetCrosswalk_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lookupServiceClient) StreamZips(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[GetZipRequest, ZipResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LookupService_ServiceDesc.Streams[0], LookupService_StreamZips_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetZipRequest, ZipResult]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LookupService_StreamZipsClient = grpc.BidiStreamingClient[GetZipRequest, ZipResult]
+
+// LookupServiceServer is the server API for LookupService service.
+// All implementations must embed UnimplementedLookupServiceServer
+// for forward compatibility.
+//
+// LookupService answers lookups from one data release, named in the
+// "usps-release" response header.
+type LookupServiceServer interface {
+	GetZip(context.Context, *GetZipRequest) (*GetZipResponse, error)
+	GetZip4(context.Context, *GetZip4Request) (*Zip4Detail, error)
+	GetZipsForCity(context.Context, *GetZipsForCityRequest) (*ZipList, error)
+	GetZipsForCounty(context.Context, *GetZipsForCountyRequest) (*ZipList, error)
+	CompleteCity(context.Context, *CompleteCityRequest) (*CompleteCityResponse, error)
+	GetCrosswalk(context.Context, *GetCrosswalkRequest) (*GetCrosswalkResponse, error)
+	// StreamZips looks up ZIPs as they're sent, returning results in order.
+	StreamZips(grpc.BidiStreamingServer[GetZipRequest, ZipResult]) error
+	mustEmbedUnimplementedLookupServiceServer()
+}
+
+// UnimplementedLookupServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLookupServiceServer struct{}
+
+func (UnimplementedLookupServiceServer) GetZip(context.Context, *GetZipRequest) (*GetZipResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetZip not implemented")
+}
+func (UnimplementedLookupServiceServer) GetZip4(context.Context, *GetZip4Request) (*Zip4Detail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetZip4 not implemented")
+}
+func (UnimplementedLookupServiceServer) GetZipsForCity(context.Context, *GetZipsForCityRequest) (*ZipList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetZipsForCity not implemented")
+}
+func (UnimplementedLookupServiceServer) GetZipsForCounty(context.Context, *GetZipsForCountyRequest) (*ZipList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetZipsForCounty not implemented")
+}
+func (UnimplementedLookupServiceServer) CompleteCity(context.Context, *CompleteCityRequest) (*CompleteCityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteCity not implemented")
+}
+func (UnimplementedLookupServiceServer) GetCrosswalk(context.Context, *GetCrosswalkRequest) (*GetCrosswalkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCrosswalk not implemented")
+}
+func (UnimplementedLookupServiceServer) StreamZips(grpc.BidiStreamingServer[GetZipRequest, ZipResult]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamZips not implemented")
+}
+func (UnimplementedLookupServiceServer) mustEmbedUnimplementedLookupServiceServer() {}
+func (UnimplementedLookupServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeLookupServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LookupServiceServer will
+// result in compilation errors.
+type UnsafeLookupServiceServer interface {
+	mustEmbedUnimplementedLookupServiceServer()
+}
+
+func RegisterLookupServiceServer(s grpc.ServiceRegistrar, srv LookupServiceServer) {
+	// If the following call pancis, it indicates UnimplementedLookupServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LookupService_ServiceDesc, srv)
+}
+
+func _LookupService_GetZip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetZipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LookupServiceServer).GetZip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LookupService_GetZip_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LookupServiceServer).GetZip(ctx, req.(*GetZipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LookupService_GetZip4_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetZip4Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LookupServiceServer).GetZip4(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LookupService_GetZip4_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LookupServiceServer).GetZip4(ctx, req.(*GetZip4Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LookupService_GetZipsForCity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetZipsForCityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LookupServiceServer).GetZipsForCity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LookupService_GetZipsForCity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LookupServiceServer).GetZipsForCity(ctx, req.(*GetZipsForCityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LookupService_GetZipsForCounty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetZipsForCountyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LookupServiceServer).GetZipsForCounty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LookupService_GetZipsForCounty_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LookupServiceServer).GetZipsForCounty(ctx, req.(*GetZipsForCountyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LookupService_CompleteCity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteCityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LookupServiceServer).CompleteCity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LookupService_CompleteCity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LookupServiceServer).CompleteCity(ctx, req.(*CompleteCityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LookupService_GetCrosswalk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCrosswalkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LookupServiceServer).GetCrosswalk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LookupService_GetCrosswalk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LookupServiceServer).GetCrosswalk(ctx, req.(*GetCrosswalkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LookupService_StreamZips_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LookupServiceServer).StreamZips(&grpc.GenericServerStream[GetZipRequest, ZipResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LookupService_StreamZipsServer = grpc.BidiStreamingServer[GetZipRequest, ZipResult]
+
+// LookupService_ServiceDesc is the grpc.ServiceDesc for LookupService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LookupService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "usps.lookup.v1.LookupService",
+	HandlerType: (*LookupServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetZip",
+			Handler:    _LookupService_GetZip_Handler,
+		},
+		{
+			MethodName: "GetZip4",
+			Handler:    _LookupService_GetZip4_Handler,
+		},
+		{
+			MethodName: "GetZipsForCity",
+			Handler:    _LookupService_GetZipsForCity_Handler,
+		},
+		{
+			MethodName: "GetZipsForCounty",
+			Handler:    _LookupService_GetZipsForCounty_Handler,
+		},
+		{
+			MethodName: "CompleteCity",
+			Handler:    _LookupService_CompleteCity_Handler,
+		},
+		{
+			MethodName: "GetCrosswalk",
+			Handler:    _LookupService_GetCrosswalk_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamZips",
+			Handler:       _LookupService_StreamZips_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "lookup.proto",
+}
@@ -0,0 +1,322 @@
+package lookup
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/corbaltcode/usps/citystate"
+	"github.com/corbaltcode/usps/fips"
+	"github.com/corbaltcode/usps/zip4"
+)
+
+// memoryZip is what's held for one ZIP.
+type memoryZip struct {
+	info    *ZipInfo
+	cities  []citystate.CityStateDetail
+	ranges  []zip4.Zip4Detail
+	records map[string]int
+}
+
+type memoryStore struct {
+	zips     map[string]*memoryZip
+	counties map[string]County
+	// cityZips maps state and city name to sorted ZIPs.
+	cityZips map[City][]string
+	// mailingCities are the mailing city names, sorted by name and state.
+	mailingCities []City
+	// countyZips maps county FIPS codes to sorted ZIPs.
+	countyZips map[string][]string
+	cityIndex  *citystate.CityIndex
+	release    string
+}
+
+// NewMemoryStore returns a store holding the records the read functions
+// yield. Either may be nil. The full ZIP+4 data takes several gigabytes.
+func NewMemoryStore(readZip4 func(yield func(zip4.Zip4Detail)) error, readCityState func(yield func(citystate.CityStateDetail)) error) (Store, error) {
+	s := &memoryStore{
+		zips:       make(map[string]*memoryZip),
+		counties:   make(map[string]County),
+		cityZips:   make(map[City][]string),
+		countyZips: make(map[string][]string),
+		cityIndex:  citystate.NewCityIndex(),
+	}
+
+	var err error
+	if readCityState != nil {
+		err = readCityState(func(d citystate.CityStateDetail) {
+			if err == nil {
+				err = s.addCityState(d)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if readZip4 != nil {
+		err = readZip4(func(d zip4.Zip4Detail) {
+			if err == nil {
+				err = s.addZip4(d)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.finish()
+	return s, nil
+}
+
+// LoadTar returns a memory store holding the records in a zip4natl tar. Its
+// release is the file version in the tar's ZIP+4 copyright record.
+func LoadTar(tarName, zip4Password, cityStatePassword string) (Store, error) {
+	header, err := zip4.ReadZip4Header(tarName, zip4Password)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := NewMemoryStore(func(yield func(zip4.Zip4Detail)) error {
+		return zip4.ReadZip4FromZip4Tar(tarName, zip4Password, yield)
+	}, func(yield func(citystate.CityStateDetail)) error {
+		return zip4.ReadCityStateFromZip4Tar(tarName, cityStatePassword, yield)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.(*memoryStore).release = header.FileVersion.Format(time.DateOnly)
+	return s, nil
+}
+
+func (s *memoryStore) zip(zip string) *memoryZip {
+	z, ok := s.zips[zip]
+	if !ok {
+		z = &memoryZip{records: make(map[string]int)}
+		s.zips[zip] = z
+	}
+	return z
+}
+
+func (s *memoryStore) county(state, number, name string) (County, error) {
+	code, err := fips.CountyCode(state, number)
+	if err != nil {
+		return County{}, err
+	}
+
+	c, ok := s.counties[code]
+	if !ok {
+		c = County{Fips: code, StateAbbreviation: state, CountyNumber: number}
+	}
+	if name != "" {
+		c.Name = name
+	}
+	s.counties[code] = c
+	return c, nil
+}
+
+func (s *memoryStore) addCityState(d citystate.CityStateDetail) error {
+	county, err := s.county(d.StateAbbreviation, d.CountyNumber, d.CountyName)
+	if err != nil {
+		return err
+	}
+
+	z := s.zip(d.ZipCode)
+	z.info = &ZipInfo{
+		ZipCode:                        d.ZipCode,
+		ZipClassificationCode:          d.ZipClassificationCode,
+		StateAbbreviation:              d.StateAbbreviation,
+		County:                         county,
+		PreferredLastLineCityStateKey:  d.PreferredLastLineCityStateKey,
+		PreferredLastLineCityStateName: d.PreferredLastLineCityStateName,
+	}
+	z.cities = append(z.cities, d)
+	s.cityIndex.Add(d)
+	s.countyZips[county.Fips] = append(s.countyZips[county.Fips], d.ZipCode)
+
+	city := City{Name: d.CityStateName, StateAbbreviation: d.StateAbbreviation}
+	s.cityZips[city] = append(s.cityZips[city], d.ZipCode)
+	if d.CityStateMailingNameIndicator == "Y" {
+		s.mailingCities = append(s.mailingCities, city)
+	}
+	return nil
+}
+
+func (s *memoryStore) addZip4(d zip4.Zip4Detail) error {
+	county, err := s.county(d.StateAbbreviation, d.CountyNumber, "")
+	if err != nil {
+		return err
+	}
+
+	d.ActionCode = ""
+	z := s.zip(d.ZipCode)
+	z.ranges = append(z.ranges, d)
+	z.records[county.Fips]++
+	if z.records[county.Fips] == 1 {
+		s.countyZips[county.Fips] = append(s.countyZips[county.Fips], d.ZipCode)
+	}
+	return nil
+}
+
+func (s *memoryStore) finish() {
+	for _, z := range s.zips {
+		sort.SliceStable(z.cities, func(i, j int) bool {
+			return z.cities[i].CityStateKey < z.cities[j].CityStateKey
+		})
+	}
+
+	for city, zips := range s.cityZips {
+		s.cityZips[city] = dedupe(zips)
+	}
+	for county, zips := range s.countyZips {
+		s.countyZips[county] = dedupe(zips)
+	}
+
+	sort.Slice(s.mailingCities, func(i, j int) bool {
+		a, b := s.mailingCities[i], s.mailingCities[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.StateAbbreviation < b.StateAbbreviation
+	})
+	cities := s.mailingCities[:0]
+	for i, c := range s.mailingCities {
+		if i == 0 || c != s.mailingCities[i-1] {
+			cities = append(cities, c)
+		}
+	}
+	s.mailingCities = cities
+}
+
+func dedupe(values []string) []string {
+	sort.Strings(values)
+	deduped := values[:0]
+	for i, v := range values {
+		if i == 0 || v != values[i-1] {
+			deduped = append(deduped, v)
+		}
+	}
+	return deduped
+}
+
+func (s *memoryStore) Zip(zip string) (ZipInfo, error) {
+	if err := checkZip(zip); err != nil {
+		return ZipInfo{}, err
+	}
+
+	z, ok := s.zips[zip]
+	if !ok || z.info == nil {
+		return ZipInfo{}, ErrNotFound
+	}
+	info := *z.info
+	info.County = s.counties[info.County.Fips]
+	return info, nil
+}
+
+func (s *memoryStore) Cities(zip string) ([]citystate.CityStateDetail, error) {
+	if err := checkZip(zip); err != nil {
+		return nil, err
+	}
+
+	z, ok := s.zips[zip]
+	if !ok || len(z.cities) == 0 {
+		return nil, ErrNotFound
+	}
+	return append([]citystate.CityStateDetail(nil), z.cities...), nil
+}
+
+func (s *memoryStore) ZipsForCity(state, city string) ([]string, error) {
+	zips := s.cityZips[City{Name: citystate.NormalizeCityName(city), StateAbbreviation: state}]
+	return append([]string(nil), zips...), nil
+}
+
+func (s *memoryStore) ZipsForCounty(fips string) ([]string, error) {
+	return append([]string(nil), s.countyZips[fips]...), nil
+}
+
+func (s *memoryStore) Counties(zip string) ([]County, error) {
+	if err := checkZip(zip); err != nil {
+		return nil, err
+	}
+
+	z, ok := s.zips[zip]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if len(z.records) == 0 {
+		info, err := s.Zip(zip)
+		if err != nil {
+			return nil, err
+		}
+		return []County{info.County}, nil
+	}
+
+	counties := make([]County, 0, len(z.records))
+	for code, n := range z.records {
+		c := s.counties[code]
+		c.Zip4Records = n
+		counties = append(counties, c)
+	}
+	sortCounties(counties)
+	return counties, nil
+}
+
+func (s *memoryStore) Zip4Ranges(zip string) ([]zip4.Zip4Detail, error) {
+	if err := checkZip(zip); err != nil {
+		return nil, err
+	}
+
+	z, ok := s.zips[zip]
+	if !ok || len(z.ranges) == 0 {
+		return nil, ErrNotFound
+	}
+	ranges := append([]zip4.Zip4Detail(nil), z.ranges...)
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].UpdateKey < ranges[j].UpdateKey
+	})
+	return ranges, nil
+}
+
+func (s *memoryStore) Zip4(code string) (zip4.Zip4Detail, error) {
+	zip, plus4, err := SplitZip4(code)
+	if err != nil {
+		return zip4.Zip4Detail{}, err
+	}
+
+	z, ok := s.zips[zip]
+	if !ok {
+		return zip4.Zip4Detail{}, ErrNotFound
+	}
+	d, ok := narrowest(z.ranges, plus4)
+	if !ok {
+		return zip4.Zip4Detail{}, ErrNotFound
+	}
+	return d, nil
+}
+
+func (s *memoryStore) CompleteCity(state, prefix string, limit int) ([]City, error) {
+	return completeCity(prefix, limit, func(prefix string) ([]City, error) {
+		var cities []City
+		i := sort.Search(len(s.mailingCities), func(i int) bool {
+			return s.mailingCities[i].Name >= prefix
+		})
+		for ; i < len(s.mailingCities) && strings.HasPrefix(s.mailingCities[i].Name, prefix); i++ {
+			if limit > 0 && len(cities) == limit {
+				break
+			}
+			if state == "" || s.mailingCities[i].StateAbbreviation == state {
+				cities = append(cities, s.mailingCities[i])
+			}
+		}
+		return cities, nil
+	})
+}
+
+func (s *memoryStore) SearchCities(state, city string, limit int) ([]citystate.CityMatch, error) {
+	return s.cityIndex.Search(state, city, limit), nil
+}
+
+func (s *memoryStore) Release() (string, error) {
+	return s.release, nil
+}
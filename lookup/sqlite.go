@@ -0,0 +1,341 @@
+package lookup
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/corbaltcode/usps/citystate"
+	"github.com/corbaltcode/usps/zip4"
+)
+
+type sqliteStore struct {
+	db *sql.DB
+
+	// cityIndex is built from the database on the first SearchCities.
+	cityIndexOnce sync.Once
+	cityIndex     *citystate.CityIndex
+	cityIndexErr  error
+}
+
+// NewSQLiteStore returns a store backed by a database created by seed-db.
+func NewSQLiteStore(db *sql.DB) Store {
+	return &sqliteStore{db: db}
+}
+
+func (s *sqliteStore) Zip(zip string) (ZipInfo, error) {
+	if err := checkZip(zip); err != nil {
+		return ZipInfo{}, err
+	}
+
+	var info ZipInfo
+	var classification, county, countyState, countyNumber, countyName, preferredKey, preferredName sql.NullString
+	err := s.db.QueryRow(`SELECT z.ZipCode, z.ZipClassificationCode, z.StateAbbreviation, z.CountyFips, c.StateAbbreviation, c.CountyNumber, c.CountyName, z.PreferredLastLineCityStateKey, z.PreferredLastLineCityStateName
+		FROM zips z LEFT JOIN counties c ON c.CountyFips = z.CountyFips WHERE z.ZipCode = ?`, zip).
+		Scan(&info.ZipCode, &classification, &info.StateAbbreviation, &county, &countyState, &countyNumber, &countyName, &preferredKey, &preferredName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ZipInfo{}, ErrNotFound
+	}
+	if err != nil {
+		return ZipInfo{}, err
+	}
+
+	info.ZipClassificationCode = classification.String
+	info.County = County{Fips: county.String, StateAbbreviation: countyState.String, CountyNumber: countyNumber.String, Name: countyName.String}
+	info.PreferredLastLineCityStateKey = preferredKey.String
+	info.PreferredLastLineCityStateName = preferredName.String
+	return info, nil
+}
+
+func (s *sqliteStore) Cities(zip string) ([]citystate.CityStateDetail, error) {
+	if err := checkZip(zip); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`SELECT k.ZipCode, k.CityStateKey, COALESCE(z.ZipClassificationCode, ''), k.CityStateName, COALESCE(k.CityStateNameAbbreviation, ''),
+			COALESCE(k.CityStateNameFacilityCode, ''), COALESCE(k.CityStateMailingNameIndicator, ''), COALESCE(k.PreferredLastLineCityStateKey, ''),
+			COALESCE(k.PreferredLastLineCityStateName, ''), COALESCE(k.CityDeliveryIndicator, ''), COALESCE(k.CarrierRouteRateSortation, ''),
+			COALESCE(k.UniqueZipNameIndicator, ''), COALESCE(k.FinanceNumber, ''), k.StateAbbreviation, COALESCE(c.CountyNumber, ''), COALESCE(c.CountyName, '')
+		FROM city_state_keys k
+		LEFT JOIN zips z ON z.ZipCode = k.ZipCode
+		LEFT JOIN counties c ON c.CountyFips = z.CountyFips
+		WHERE k.ZipCode = ? ORDER BY k.CityStateKey`, zip)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var details []citystate.CityStateDetail
+	for rows.Next() {
+		d := citystate.CityStateDetail{CopyrightDetailCode: citystate.CityStateCopyrightDetailCodeDetail}
+		err := rows.Scan(&d.ZipCode, &d.CityStateKey, &d.ZipClassificationCode, &d.CityStateName, &d.CityStateNameAbbreviation,
+			&d.CityStateNameFacilityCode, &d.CityStateMailingNameIndicator, &d.PreferredLastLineCityStateKey,
+			&d.PreferredLastLineCityStateName, &d.CityDeliveryIndicator, &d.CarrierRouteRateSortation,
+			&d.UniqueZipNameIndicator, &d.FinanceNumber, &d.StateAbbreviation, &d.CountyNumber, &d.CountyName)
+		if err != nil {
+			return nil, err
+		}
+		details = append(details, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(details) == 0 {
+		return nil, ErrNotFound
+	}
+	return details, nil
+}
+
+func (s *sqliteStore) ZipsForCity(state, city string) ([]string, error) {
+	return s.strings(`SELECT DISTINCT ZipCode FROM city_state_keys WHERE StateAbbreviation = ? AND CityStateName = ? ORDER BY ZipCode`, state, citystate.NormalizeCityName(city))
+}
+
+func (s *sqliteStore) ZipsForCounty(fips string) ([]string, error) {
+	return s.strings(`SELECT ZipCode FROM zip4_ranges WHERE CountyFips = ? UNION SELECT ZipCode FROM zips WHERE CountyFips = ? ORDER BY 1`, fips, fips)
+}
+
+func (s *sqliteStore) Counties(zip string) ([]County, error) {
+	if err := checkZip(zip); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`SELECT r.CountyFips, c.StateAbbreviation, c.CountyNumber, COALESCE(c.CountyName, ''), COUNT(*)
+		FROM zip4_ranges r JOIN counties c ON c.CountyFips = r.CountyFips
+		WHERE r.ZipCode = ? GROUP BY r.CountyFips, c.StateAbbreviation, c.CountyNumber, c.CountyName`, zip)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counties []County
+	for rows.Next() {
+		var c County
+		if err := rows.Scan(&c.Fips, &c.StateAbbreviation, &c.CountyNumber, &c.Name, &c.Zip4Records); err != nil {
+			return nil, err
+		}
+		counties = append(counties, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(counties) == 0 {
+		info, err := s.Zip(zip)
+		if err != nil {
+			return nil, err
+		}
+		return []County{info.County}, nil
+	}
+
+	sortCounties(counties)
+	return counties, nil
+}
+
+func (s *sqliteStore) Zip4Ranges(zip string) ([]zip4.Zip4Detail, error) {
+	if err := checkZip(zip); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`SELECT r.ZipCode, COALESCE(r.UpdateKey, ''), r.RecordTypeCode, c.StateAbbreviation, c.CountyNumber, r.Sector, r.Plus4Low, r.Plus4High
+		FROM zip4_ranges r JOIN counties c ON c.CountyFips = r.CountyFips
+		WHERE r.ZipCode = ? ORDER BY r.UpdateKey`, zip)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ranges []zip4.Zip4Detail
+	for rows.Next() {
+		var d zip4.Zip4Detail
+		var sector string
+		var low, high sql.NullInt64
+		if err := rows.Scan(&d.ZipCode, &d.UpdateKey, &d.RecordTypeCode, &d.StateAbbreviation, &d.CountyNumber, &sector, &low, &high); err != nil {
+			return nil, err
+		}
+		d.Plus4LowNumber = zip4Number(sector, low)
+		d.Plus4HighNumber = zip4Number(sector, high)
+		ranges = append(ranges, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(ranges) == 0 {
+		return nil, ErrNotFound
+	}
+	return ranges, nil
+}
+
+// zip4Number rebuilds an add-on from its sector and number, which is null
+// for non-deliverable add-ons.
+func zip4Number(sector string, n sql.NullInt64) zip4.Zip4Number {
+	if !n.Valid {
+		return zip4.NonDeliverableZip4Number(sector)
+	}
+	return zip4.Zip4NumberFromInt(int(n.Int64))
+}
+
+func (s *sqliteStore) Zip4(code string) (zip4.Zip4Detail, error) {
+	zip, plus4, err := SplitZip4(code)
+	if err != nil {
+		return zip4.Zip4Detail{}, err
+	}
+	n, err := plus4.Int()
+	if err != nil {
+		return zip4.Zip4Detail{}, err
+	}
+
+	rows, err := s.db.Query(`SELECT r.ZipCode, COALESCE(r.UpdateKey, ''), r.RecordTypeCode, c.StateAbbreviation, c.CountyNumber, r.Plus4Low, r.Plus4High
+		FROM zip4_ranges r JOIN counties c ON c.CountyFips = r.CountyFips
+		WHERE r.ZipCode = ? AND r.Deliverable = 1 AND r.Plus4Low <= ? AND r.Plus4High >= ?`, zip, n, n)
+	if err != nil {
+		return zip4.Zip4Detail{}, err
+	}
+	defer rows.Close()
+
+	var ranges []zip4.Zip4Detail
+	for rows.Next() {
+		var d zip4.Zip4Detail
+		var low, high int
+		if err := rows.Scan(&d.ZipCode, &d.UpdateKey, &d.RecordTypeCode, &d.StateAbbreviation, &d.CountyNumber, &low, &high); err != nil {
+			return zip4.Zip4Detail{}, err
+		}
+		d.Plus4LowNumber = zip4.Zip4NumberFromInt(low)
+		d.Plus4HighNumber = zip4.Zip4NumberFromInt(high)
+		ranges = append(ranges, d)
+	}
+	if err := rows.Err(); err != nil {
+		return zip4.Zip4Detail{}, err
+	}
+
+	d, ok := narrowest(ranges, plus4)
+	if !ok {
+		return zip4.Zip4Detail{}, ErrNotFound
+	}
+	return d, nil
+}
+
+func (s *sqliteStore) CompleteCity(state, prefix string, limit int) ([]City, error) {
+	return completeCity(prefix, limit, func(prefix string) ([]City, error) {
+		return s.completeCity(state, prefix, limit)
+	})
+}
+
+// completeCity returns up to limit mailing city names starting with a
+// normalized prefix.
+func (s *sqliteStore) completeCity(state, prefix string, limit int) ([]City, error) {
+	query := `SELECT DISTINCT CityStateName, StateAbbreviation FROM city_state_keys WHERE CityStateMailingNameIndicator = 'Y' AND CityStateName >= ?`
+	args := []any{prefix}
+	if upper, ok := prefixUpperBound(prefix); ok {
+		query += ` AND CityStateName < ?`
+		args = append(args, upper)
+	}
+	if state != "" {
+		query += ` AND StateAbbreviation = ?`
+		args = append(args, state)
+	}
+	query += ` ORDER BY CityStateName, StateAbbreviation`
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cities []City
+	for rows.Next() {
+		var c City
+		if err := rows.Scan(&c.Name, &c.StateAbbreviation); err != nil {
+			return nil, err
+		}
+		cities = append(cities, c)
+	}
+	return cities, rows.Err()
+}
+
+// SearchCities searches an index of the database's city names, which takes
+// a moment to build the first time.
+func (s *sqliteStore) SearchCities(state, city string, limit int) ([]citystate.CityMatch, error) {
+	s.cityIndexOnce.Do(func() {
+		s.cityIndex, s.cityIndexErr = s.buildCityIndex()
+	})
+	if s.cityIndexErr != nil {
+		return nil, s.cityIndexErr
+	}
+	return s.cityIndex.Search(state, city, limit), nil
+}
+
+func (s *sqliteStore) buildCityIndex() (*citystate.CityIndex, error) {
+	rows, err := s.db.Query(`SELECT ZipCode, CityStateName, COALESCE(CityStateNameAbbreviation, ''), StateAbbreviation FROM city_state_keys`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	index := citystate.NewCityIndex()
+	for rows.Next() {
+		var d citystate.CityStateDetail
+		if err := rows.Scan(&d.ZipCode, &d.CityStateName, &d.CityStateNameAbbreviation, &d.StateAbbreviation); err != nil {
+			return nil, err
+		}
+		index.Add(d)
+	}
+	return index, rows.Err()
+}
+
+// Release returns the latest release recorded by seed-db or, if none was,
+// the time of the latest load.
+func (s *sqliteStore) Release() (string, error) {
+	var release string
+	err := s.db.QueryRow(`SELECT Release FROM load_metadata WHERE Release IS NOT NULL AND Release != '' ORDER BY rowid DESC LIMIT 1`).Scan(&release)
+	if err == nil || !errors.Is(err, sql.ErrNoRows) {
+		return release, err
+	}
+
+	var loadedAt sql.NullInt64
+	if err := s.db.QueryRow(`SELECT MAX(LoadedAt) FROM load_metadata`).Scan(&loadedAt); err != nil {
+		return "", err
+	}
+	if !loadedAt.Valid {
+		return "", nil
+	}
+	return "loaded-" + strconv.FormatInt(loadedAt.Int64, 10), nil
+}
+
+func (s *sqliteStore) strings(query string, args ...any) ([]string, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
+
+// prefixUpperBound returns the least string greater than every string
+// starting with prefix, so a prefix search can use an index. There's none
+// for an empty prefix.
+func prefixUpperBound(prefix string) (string, bool) {
+	b := []byte(prefix)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < 0xff {
+			b[i]++
+			return string(b[:i+1]), true
+		}
+	}
+	return "", false
+}
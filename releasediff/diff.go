@@ -0,0 +1,252 @@
+package releasediff
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Changeset lists what changed between two releases. Every list is sorted
+// by ZIP.
+type Changeset struct {
+	ZipsAdded           []string         `json:"zips_added"`
+	ZipsRetired         []string         `json:"zips_retired"`
+	CountyReassignments []SetChange      `json:"county_reassignments"`
+	CityNameChanges     []CityNameChange `json:"city_name_changes"`
+	RecordTypeChanges   []SetChange      `json:"record_type_changes"`
+	RangeSplits         []RangeChange    `json:"range_splits"`
+	RangeMerges         []RangeChange    `json:"range_merges"`
+}
+
+// SetChange is a change to a set of values (counties or record types) for
+// a ZIP.
+type SetChange struct {
+	Zipcode string   `json:"zipcode"`
+	Old     []string `json:"old"`
+	New     []string `json:"new"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// CityNameChange is a change to a ZIP's city names or preferred city name.
+type CityNameChange struct {
+	Zipcode      string   `json:"zipcode"`
+	OldPreferred string   `json:"old_preferred"`
+	NewPreferred string   `json:"new_preferred"`
+	Added        []string `json:"added"`
+	Removed      []string `json:"removed"`
+}
+
+// RangeChange is an add-on range split into, or merged from, adjacent
+// ranges.
+type RangeChange struct {
+	Zipcode string       `json:"zipcode"`
+	Old     []Plus4Range `json:"old"`
+	New     []Plus4Range `json:"new"`
+}
+
+// Diff compares an old release to a new one.
+func Diff(old, new *Snapshot) *Changeset {
+	c := &Changeset{}
+
+	for zip := range old.Zips {
+		if _, ok := new.Zips[zip]; !ok {
+			c.ZipsRetired = append(c.ZipsRetired, zip)
+		}
+	}
+	sort.Strings(c.ZipsRetired)
+
+	var zips []string
+	for zip := range new.Zips {
+		if _, ok := old.Zips[zip]; ok {
+			zips = append(zips, zip)
+		} else {
+			c.ZipsAdded = append(c.ZipsAdded, zip)
+		}
+	}
+	sort.Strings(c.ZipsAdded)
+	sort.Strings(zips)
+
+	for _, zip := range zips {
+		o, n := old.Zips[zip], new.Zips[zip]
+
+		if ch, ok := diffSets(zip, o.Counties, n.Counties); ok {
+			c.CountyReassignments = append(c.CountyReassignments, ch)
+		}
+		if ch, ok := diffSets(zip, o.RecordTypes, n.RecordTypes); ok {
+			c.RecordTypeChanges = append(c.RecordTypeChanges, ch)
+		}
+
+		added, removed := setDifference(n.CityNames, o.CityNames), setDifference(o.CityNames, n.CityNames)
+		if len(added) > 0 || len(removed) > 0 || o.PreferredCity != n.PreferredCity {
+			c.CityNameChanges = append(c.CityNameChanges, CityNameChange{
+				Zipcode:      zip,
+				OldPreferred: o.PreferredCity,
+				NewPreferred: n.PreferredCity,
+				Added:        added,
+				Removed:      removed,
+			})
+		}
+
+		for _, r := range rangesCoveredBy(o.Ranges, n.Ranges) {
+			c.RangeSplits = append(c.RangeSplits, RangeChange{Zipcode: zip, Old: []Plus4Range{r.outer}, New: r.parts})
+		}
+		for _, r := range rangesCoveredBy(n.Ranges, o.Ranges) {
+			c.RangeMerges = append(c.RangeMerges, RangeChange{Zipcode: zip, Old: r.parts, New: []Plus4Range{r.outer}})
+		}
+	}
+
+	return c
+}
+
+func diffSets(zip string, old, new map[string]bool) (SetChange, bool) {
+	added, removed := setDifference(new, old), setDifference(old, new)
+	if len(added) == 0 && len(removed) == 0 {
+		return SetChange{}, false
+	}
+	return SetChange{
+		Zipcode: zip,
+		Old:     sortedKeys(old),
+		New:     sortedKeys(new),
+		Added:   added,
+		Removed: removed,
+	}, true
+}
+
+// setDifference returns the sorted values in a but not in b.
+func setDifference(a, b map[string]bool) []string {
+	var diff []string
+	for v := range a {
+		if !b[v] {
+			diff = append(diff, v)
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type coveredRange struct {
+	outer Plus4Range
+	parts []Plus4Range
+}
+
+// rangesCoveredBy finds ranges in outers, absent from parts, that are
+// exactly covered by two or more ranges in parts. Both lists must be
+// sorted and deduplicated.
+func rangesCoveredBy(outers, parts []Plus4Range) []coveredRange {
+	var found []coveredRange
+
+	for _, outer := range outers {
+		i := sort.Search(len(parts), func(i int) bool {
+			return parts[i].Low >= outer.Low
+		})
+		if i < len(parts) && parts[i] == outer {
+			continue
+		}
+
+		var inside []Plus4Range
+		for ; i < len(parts) && parts[i].Low <= outer.High; i++ {
+			if parts[i].High <= outer.High {
+				inside = append(inside, parts[i])
+			}
+		}
+		if len(inside) < 2 || inside[0].Low != outer.Low {
+			continue
+		}
+
+		covered := int(inside[0].High)
+		for _, p := range inside[1:] {
+			if int(p.Low) > covered+1 {
+				break
+			}
+			covered = max(covered, int(p.High))
+		}
+		if covered == int(outer.High) {
+			found = append(found, coveredRange{outer: outer, parts: inside})
+		}
+	}
+
+	return found
+}
+
+// WriteSummary writes counts of each kind of change and up to examples
+// changes of each kind as plain text.
+func (c *Changeset) WriteSummary(w io.Writer, examples int) error {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%-22s %8d\n", "ZIPs added", len(c.ZipsAdded))
+	fmt.Fprintf(&sb, "%-22s %8d\n", "ZIPs retired", len(c.ZipsRetired))
+	fmt.Fprintf(&sb, "%-22s %8d\n", "County reassignments", len(c.CountyReassignments))
+	fmt.Fprintf(&sb, "%-22s %8d\n", "City name changes", len(c.CityNameChanges))
+	fmt.Fprintf(&sb, "%-22s %8d\n", "Record type changes", len(c.RecordTypeChanges))
+	fmt.Fprintf(&sb, "%-22s %8d\n", "Range splits", len(c.RangeSplits))
+	fmt.Fprintf(&sb, "%-22s %8d\n", "Range merges", len(c.RangeMerges))
+
+	section := func(title string, n int, line func(i int) string) {
+		if n == 0 {
+			return
+		}
+		fmt.Fprintf(&sb, "\n%v:\n", title)
+		for i := 0; i < n && i < examples; i++ {
+			fmt.Fprintf(&sb, "  %v\n", line(i))
+		}
+		if n > examples {
+			fmt.Fprintf(&sb, "  ... and %v more\n", n-examples)
+		}
+	}
+
+	section("ZIPs added", len(c.ZipsAdded), func(i int) string {
+		return c.ZipsAdded[i]
+	})
+	section("ZIPs retired", len(c.ZipsRetired), func(i int) string {
+		return c.ZipsRetired[i]
+	})
+	section("County reassignments", len(c.CountyReassignments), func(i int) string {
+		ch := c.CountyReassignments[i]
+		return fmt.Sprintf("%v  %v -> %v", ch.Zipcode, strings.Join(ch.Old, ","), strings.Join(ch.New, ","))
+	})
+	section("City name changes", len(c.CityNameChanges), func(i int) string {
+		ch := c.CityNameChanges[i]
+		s := fmt.Sprintf("%v  preferred %q -> %q", ch.Zipcode, ch.OldPreferred, ch.NewPreferred)
+		if len(ch.Added) > 0 {
+			s += fmt.Sprintf("; added %v", strings.Join(ch.Added, ", "))
+		}
+		if len(ch.Removed) > 0 {
+			s += fmt.Sprintf("; removed %v", strings.Join(ch.Removed, ", "))
+		}
+		return s
+	})
+	section("Record type changes", len(c.RecordTypeChanges), func(i int) string {
+		ch := c.RecordTypeChanges[i]
+		return fmt.Sprintf("%v  %v -> %v", ch.Zipcode, strings.Join(ch.Old, ""), strings.Join(ch.New, ""))
+	})
+	section("Range splits", len(c.RangeSplits), func(i int) string {
+		ch := c.RangeSplits[i]
+		return fmt.Sprintf("%v  %v -> %v", ch.Zipcode, ch.Old[0], joinRanges(ch.New))
+	})
+	section("Range merges", len(c.RangeMerges), func(i int) string {
+		ch := c.RangeMerges[i]
+		return fmt.Sprintf("%v  %v -> %v", ch.Zipcode, joinRanges(ch.Old), ch.New[0])
+	})
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func joinRanges(ranges []Plus4Range) string {
+	s := make([]string, len(ranges))
+	for i, r := range ranges {
+		s[i] = r.String()
+	}
+	return strings.Join(s, ",")
+}
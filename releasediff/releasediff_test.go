@@ -0,0 +1,79 @@
+package releasediff
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/corbaltcode/usps/citystate"
+	"github.com/corbaltcode/usps/zip4"
+)
+
+func detail(zip, recordType, county, low, high string) zip4.Zip4Detail {
+	return zip4.Zip4Detail{
+		ZipCode:           zip,
+		RecordTypeCode:    recordType,
+		StateAbbreviation: "VA",
+		CountyNumber:      county,
+		Plus4LowNumber:    zip4.Zip4Number(low),
+		Plus4HighNumber:   zip4.Zip4Number(high),
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := NewSnapshot()
+	old.AddZip4(detail("22201", "S", "013", "0001", "0010"))
+	old.AddZip4(detail("22201", "S", "013", "0020", "0029"))
+	old.AddZip4(detail("22201", "S", "013", "0030", "0039"))
+	old.AddZip4(detail("22202", "S", "013", "0001", "0010"))
+	old.AddZip4(detail("22203", "S", "013", "0001", "0010"))
+	old.AddCityState(citystate.CityStateDetail{ZipCode: "22201", CityStateName: "ARLINGTON", PreferredLastLineCityStateName: "ARLINGTON"})
+	if err := old.finish(); err != nil {
+		t.Fatal(err)
+	}
+
+	new := NewSnapshot()
+	new.AddZip4(detail("22201", "S", "013", "0001", "0005"))
+	new.AddZip4(detail("22201", "S", "013", "0006", "0010"))
+	new.AddZip4(detail("22201", "S", "013", "0020", "0039"))
+	new.AddZip4(detail("22201", "S", "013", "00ND", "00ND"))
+	new.AddZip4(detail("22202", "P", "510", "0001", "0010"))
+	new.AddZip4(detail("22204", "S", "013", "0001", "0010"))
+	new.AddCityState(citystate.CityStateDetail{ZipCode: "22201", CityStateName: "ARLINGTON", PreferredLastLineCityStateName: "ARLINGTON"})
+	new.AddCityState(citystate.CityStateDetail{ZipCode: "22201", CityStateName: "CLARENDON", PreferredLastLineCityStateName: "ARLINGTON"})
+	if err := new.finish(); err != nil {
+		t.Fatal(err)
+	}
+
+	c := Diff(old, new)
+
+	if !reflect.DeepEqual(c.ZipsAdded, []string{"22204"}) {
+		t.Errorf("ZipsAdded = %v", c.ZipsAdded)
+	}
+	if !reflect.DeepEqual(c.ZipsRetired, []string{"22203"}) {
+		t.Errorf("ZipsRetired = %v", c.ZipsRetired)
+	}
+
+	wantCounties := []SetChange{{Zipcode: "22202", Old: []string{"51013"}, New: []string{"51510"}, Added: []string{"51510"}, Removed: []string{"51013"}}}
+	if !reflect.DeepEqual(c.CountyReassignments, wantCounties) {
+		t.Errorf("CountyReassignments = %+v", c.CountyReassignments)
+	}
+
+	wantTypes := []SetChange{{Zipcode: "22202", Old: []string{"S"}, New: []string{"P"}, Added: []string{"P"}, Removed: []string{"S"}}}
+	if !reflect.DeepEqual(c.RecordTypeChanges, wantTypes) {
+		t.Errorf("RecordTypeChanges = %+v", c.RecordTypeChanges)
+	}
+
+	if len(c.CityNameChanges) != 1 || !reflect.DeepEqual(c.CityNameChanges[0].Added, []string{"CLARENDON"}) {
+		t.Errorf("CityNameChanges = %+v", c.CityNameChanges)
+	}
+
+	wantSplits := []RangeChange{{Zipcode: "22201", Old: []Plus4Range{{1, 10}}, New: []Plus4Range{{1, 5}, {6, 10}}}}
+	if !reflect.DeepEqual(c.RangeSplits, wantSplits) {
+		t.Errorf("RangeSplits = %+v", c.RangeSplits)
+	}
+
+	wantMerges := []RangeChange{{Zipcode: "22201", Old: []Plus4Range{{20, 29}, {30, 39}}, New: []Plus4Range{{20, 39}}}}
+	if !reflect.DeepEqual(c.RangeMerges, wantMerges) {
+		t.Errorf("RangeMerges = %+v", c.RangeMerges)
+	}
+}
@@ -0,0 +1,181 @@
+// Package releasediff compares two USPS releases of ZIP+4 and City State
+// data.
+package releasediff
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/corbaltcode/usps/citystate"
+	"github.com/corbaltcode/usps/fips"
+	"github.com/corbaltcode/usps/zip4"
+)
+
+// Plus4Range is an inclusive range of ZIP+4 add-on codes.
+type Plus4Range struct {
+	Low  uint16 `json:"low"`
+	High uint16 `json:"high"`
+}
+
+func (r Plus4Range) String() string {
+	return fmt.Sprintf("%04d-%04d", r.Low, r.High)
+}
+
+// Snapshot summarizes a release per ZIP.
+type Snapshot struct {
+	Zips map[string]*ZipSnapshot
+}
+
+// ZipSnapshot is what a release says about one ZIP.
+type ZipSnapshot struct {
+	Counties      map[string]bool
+	RecordTypes   map[string]bool
+	CityNames     map[string]bool
+	PreferredCity string
+	// Ranges are the ZIP's distinct numeric add-on ranges, sorted.
+	Ranges []Plus4Range
+
+	err error
+}
+
+func NewSnapshot() *Snapshot {
+	return &Snapshot{Zips: make(map[string]*ZipSnapshot)}
+}
+
+func (s *Snapshot) zip(zip string) *ZipSnapshot {
+	z, ok := s.Zips[zip]
+	if !ok {
+		z = &ZipSnapshot{
+			Counties:    make(map[string]bool),
+			RecordTypes: make(map[string]bool),
+			CityNames:   make(map[string]bool),
+		}
+		s.Zips[zip] = z
+	}
+	return z
+}
+
+// AddZip4 adds a ZIP+4 record. It has the signature expected by
+// zip4.ReadZip4FromZip4Tar.
+func (s *Snapshot) AddZip4(detail zip4.Zip4Detail) {
+	z := s.zip(detail.ZipCode)
+
+	county, err := fips.CountyCode(detail.StateAbbreviation, detail.CountyNumber)
+	if err != nil {
+		if z.err == nil {
+			z.err = fmt.Errorf("ZIP %s: %w", detail.ZipCode, err)
+		}
+	} else {
+		z.Counties[county] = true
+	}
+
+	z.RecordTypes[detail.RecordTypeCode] = true
+
+	low, errLow := detail.Plus4LowNumber.Int()
+	high, errHigh := detail.Plus4HighNumber.Int()
+	if errLow == nil && errHigh == nil && low <= high {
+		z.Ranges = append(z.Ranges, Plus4Range{Low: uint16(low), High: uint16(high)})
+	}
+}
+
+// AddCityState adds a City State record. It has the signature expected by
+// zip4.ReadCityStateFromZip4Tar.
+func (s *Snapshot) AddCityState(detail citystate.CityStateDetail) {
+	z := s.zip(detail.ZipCode)
+	z.CityNames[detail.CityStateName] = true
+	z.PreferredCity = detail.PreferredLastLineCityStateName
+}
+
+// finish sorts and deduplicates ranges and reports the first error seen
+// while adding records.
+func (s *Snapshot) finish() error {
+	for _, z := range s.Zips {
+		if z.err != nil {
+			return z.err
+		}
+
+		sort.Slice(z.Ranges, func(i, j int) bool {
+			if z.Ranges[i].Low != z.Ranges[j].Low {
+				return z.Ranges[i].Low < z.Ranges[j].Low
+			}
+			return z.Ranges[i].High < z.Ranges[j].High
+		})
+
+		deduped := z.Ranges[:0]
+		for i, r := range z.Ranges {
+			if i == 0 || r != z.Ranges[i-1] {
+				deduped = append(deduped, r)
+			}
+		}
+		z.Ranges = deduped
+	}
+
+	return nil
+}
+
+// LoadTar reads a release from a USPS zip4natl tar.
+func LoadTar(tarName, zip4Password, cityStatePassword string) (*Snapshot, error) {
+	s := NewSnapshot()
+
+	if err := zip4.ReadZip4FromZip4Tar(tarName, zip4Password, s.AddZip4); err != nil {
+		return nil, fmt.Errorf("error processing ZIP+4 data: %v", err)
+	}
+	if err := zip4.ReadCityStateFromZip4Tar(tarName, cityStatePassword, s.AddCityState); err != nil {
+		return nil, fmt.Errorf("error processing city state data: %v", err)
+	}
+
+	return s, s.finish()
+}
+
+// LoadDatabase reads a release from a database created by seed-db.
+func LoadDatabase(db *sql.DB) (*Snapshot, error) {
+	s := NewSnapshot()
+
+	rows, err := db.Query(`SELECT r.ZipCode, r.RecordTypeCode, c.StateAbbreviation, c.CountyNumber, r.Sector, r.Plus4Low, r.Plus4High
+		FROM zip4_ranges r JOIN counties c ON c.CountyFips = r.CountyFips`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d zip4.Zip4Detail
+		var sector string
+		var low, high sql.NullInt64
+		if err := rows.Scan(&d.ZipCode, &d.RecordTypeCode, &d.StateAbbreviation, &d.CountyNumber, &sector, &low, &high); err != nil {
+			return nil, err
+		}
+		// Non-deliverable ranges are skipped by AddZip4 either way.
+		if low.Valid && high.Valid {
+			d.Plus4LowNumber = zip4.Zip4NumberFromInt(int(low.Int64))
+			d.Plus4HighNumber = zip4.Zip4NumberFromInt(int(high.Int64))
+		} else {
+			d.Plus4LowNumber = zip4.NonDeliverableZip4Number(sector)
+			d.Plus4HighNumber = zip4.NonDeliverableZip4Number(sector)
+		}
+		s.AddZip4(d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = db.Query(`SELECT ZipCode, CityStateName, PreferredLastLineCityStateName FROM city_state_keys`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d citystate.CityStateDetail
+		if err := rows.Scan(&d.ZipCode, &d.CityStateName, &d.PreferredLastLineCityStateName); err != nil {
+			return nil, err
+		}
+		s.AddCityState(d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return s, s.finish()
+}
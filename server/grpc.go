@@ -0,0 +1,304 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/corbaltcode/usps/citystate"
+	"github.com/corbaltcode/usps/lookup"
+	"github.com/corbaltcode/usps/lookup/lookuppb"
+	"github.com/corbaltcode/usps/zip4"
+	"github.com/corbaltcode/usps/ziptocounty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// releaseHeader names the data release in gRPC response headers.
+const releaseHeader = "usps-release"
+
+// lookupService implements lookuppb.LookupServiceServer with a Server's
+// store.
+type lookupService struct {
+	lookuppb.UnimplementedLookupServiceServer
+	s *Server
+}
+
+// RegisterGRPC registers the LookupService on a gRPC server, answering from
+// the same store as HTTP requests.
+func (s *Server) RegisterGRPC(g *grpc.Server) {
+	lookuppb.RegisterLookupServiceServer(g, &lookupService{s: s})
+}
+
+// GRPCOptions returns server options that count gRPC requests in the
+// server's metrics.
+func (s *Server) GRPCOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(s.metrics.unaryInterceptor),
+		grpc.ChainStreamInterceptor(s.metrics.streamInterceptor),
+	}
+}
+
+// store returns the current store, sending its release as a header. The
+// caller must call done when it's finished with the store.
+func (l *lookupService) store(ctx context.Context) (store lookup.Store, done func(), err error) {
+	store, release, done := l.s.acquire()
+	if store == nil {
+		done()
+		return nil, nil, status.Error(codes.Unavailable, "data not loaded")
+	}
+	if release != "" {
+		grpc.SetHeader(ctx, metadata.Pairs(releaseHeader, release))
+	}
+	return store, done, nil
+}
+
+func grpcError(err error) error {
+	var invalid *lookup.InvalidError
+	switch {
+	case errors.Is(err, lookup.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.As(err, &invalid):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func (l *lookupService) GetZip(ctx context.Context, req *lookuppb.GetZipRequest) (*lookuppb.GetZipResponse, error) {
+	store, done, err := l.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	resp, err := getZip(store, req.Zip)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return resp, nil
+}
+
+func getZip(store lookup.Store, zip string) (*lookuppb.GetZipResponse, error) {
+	info, err := store.Zip(zip)
+	if err != nil {
+		return nil, err
+	}
+	cities, err := store.Cities(zip)
+	if err != nil && !errors.Is(err, lookup.ErrNotFound) {
+		return nil, err
+	}
+	counties, err := store.Counties(zip)
+	if err != nil {
+		return nil, err
+	}
+	crosswalk, err := crosswalk(store, zip, ziptocounty.CrosswalkOptions{})
+	if err != nil && !errors.Is(err, lookup.ErrNotFound) {
+		return nil, err
+	}
+
+	resp := &lookuppb.GetZipResponse{Zip: zipInfoProto(info), Crosswalk: crosswalk}
+	for _, c := range cities {
+		resp.Cities = append(resp.Cities, cityStateProto(c))
+	}
+	for _, c := range counties {
+		resp.Counties = append(resp.Counties, countyProto(c))
+	}
+	return resp, nil
+}
+
+func (l *lookupService) GetZip4(ctx context.Context, req *lookuppb.GetZip4Request) (*lookuppb.Zip4Detail, error) {
+	store, done, err := l.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	d, err := store.Zip4(req.Code)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return zip4Proto(d), nil
+}
+
+func (l *lookupService) GetZipsForCity(ctx context.Context, req *lookuppb.GetZipsForCityRequest) (*lookuppb.ZipList, error) {
+	store, done, err := l.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	zips, err := store.ZipsForCity(normalizeState(req.State), req.Name)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &lookuppb.ZipList{Zips: zips}, nil
+}
+
+func (l *lookupService) GetZipsForCounty(ctx context.Context, req *lookuppb.GetZipsForCountyRequest) (*lookuppb.ZipList, error) {
+	store, done, err := l.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	if !fipsPattern.MatchString(req.Fips) {
+		return nil, grpcError(&lookup.InvalidError{What: "county FIPS code", Value: req.Fips})
+	}
+	zips, err := store.ZipsForCounty(req.Fips)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &lookuppb.ZipList{Zips: zips}, nil
+}
+
+func (l *lookupService) CompleteCity(ctx context.Context, req *lookuppb.CompleteCityRequest) (*lookuppb.CompleteCityResponse, error) {
+	store, done, err := l.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	if req.Limit < 0 {
+		return nil, grpcError(&lookup.InvalidError{What: "limit", Value: strconv.Itoa(int(req.Limit))})
+	}
+	cities, err := store.CompleteCity(normalizeState(req.State), req.Prefix, int(req.Limit))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &lookuppb.CompleteCityResponse{}
+	for _, c := range cities {
+		resp.Cities = append(resp.Cities, &lookuppb.City{Name: c.Name, StateAbbreviation: c.StateAbbreviation})
+	}
+	return resp, nil
+}
+
+func (l *lookupService) GetCrosswalk(ctx context.Context, req *lookuppb.GetCrosswalkRequest) (*lookuppb.GetCrosswalkResponse, error) {
+	store, done, err := l.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	entries, err := crosswalk(store, req.Zip, ziptocounty.CrosswalkOptions{DeliverableOnly: req.DeliverableOnly, RecordTypes: req.RecordTypes})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &lookuppb.GetCrosswalkResponse{Entries: entries}, nil
+}
+
+func (l *lookupService) StreamZips(stream lookuppb.LookupService_StreamZipsServer) error {
+	store, done, err := l.store(stream.Context())
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		result := &lookuppb.ZipResult{Zip: req.Zip}
+		resp, err := getZip(store, req.Zip)
+		switch {
+		case err == nil:
+			result.Found, result.Response = true, resp
+		case !errors.Is(err, lookup.ErrNotFound):
+			result.Error = err.Error()
+		}
+		if err := stream.Send(result); err != nil {
+			return err
+		}
+	}
+}
+
+// crosswalk weights a ZIP's counties by its ZIP+4 records.
+func crosswalk(store lookup.Store, zip string, opts ziptocounty.CrosswalkOptions) ([]*lookuppb.CrosswalkEntry, error) {
+	ranges, err := store.Zip4Ranges(zip)
+	if err != nil {
+		return nil, err
+	}
+
+	b := ziptocounty.NewCrosswalkBuilder(opts)
+	for _, d := range ranges {
+		b.Add(d)
+	}
+	entries, err := b.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	var pbs []*lookuppb.CrosswalkEntry
+	for _, e := range entries {
+		pbs = append(pbs, &lookuppb.CrosswalkEntry{
+			ZipCode:          e.Zipcode,
+			CountyFips:       e.CountyFips,
+			AddOnCount:       int32(e.AddOnCount),
+			ResidentialRatio: e.ResidentialRatio,
+			BusinessRatio:    e.BusinessRatio,
+			OtherRatio:       e.OtherRatio,
+			TotalRatio:       e.TotalRatio,
+			Primary:          e.Primary,
+		})
+	}
+	return pbs, nil
+}
+
+func zipInfoProto(info lookup.ZipInfo) *lookuppb.ZipInfo {
+	return &lookuppb.ZipInfo{
+		ZipCode:                        info.ZipCode,
+		ZipClassificationCode:          info.ZipClassificationCode,
+		StateAbbreviation:              info.StateAbbreviation,
+		County:                         countyProto(info.County),
+		PreferredLastLineCityStateKey:  info.PreferredLastLineCityStateKey,
+		PreferredLastLineCityStateName: info.PreferredLastLineCityStateName,
+	}
+}
+
+func countyProto(c lookup.County) *lookuppb.County {
+	return &lookuppb.County{
+		Fips:              c.Fips,
+		StateAbbreviation: c.StateAbbreviation,
+		CountyNumber:      c.CountyNumber,
+		Name:              c.Name,
+		Zip4Records:       int32(c.Zip4Records),
+	}
+}
+
+func zip4Proto(d zip4.Zip4Detail) *lookuppb.Zip4Detail {
+	return &lookuppb.Zip4Detail{
+		ZipCode:           d.ZipCode,
+		UpdateKey:         d.UpdateKey,
+		ActionCode:        d.ActionCode,
+		RecordTypeCode:    d.RecordTypeCode,
+		StateAbbreviation: d.StateAbbreviation,
+		CountyNumber:      d.CountyNumber,
+		Plus4LowNumber:    string(d.Plus4LowNumber),
+		Plus4HighNumber:   string(d.Plus4HighNumber),
+	}
+}
+
+func cityStateProto(d citystate.CityStateDetail) *lookuppb.CityStateDetail {
+	return &lookuppb.CityStateDetail{
+		CopyrightDetailCode:            d.CopyrightDetailCode,
+		ZipCode:                        d.ZipCode,
+		CityStateKey:                   d.CityStateKey,
+		ZipClassificationCode:          d.ZipClassificationCode,
+		CityStateName:                  d.CityStateName,
+		CityStateNameAbbreviation:      d.CityStateNameAbbreviation,
+		CityStateNameFacilityCode:      d.CityStateNameFacilityCode,
+		CityStateMailingNameIndicator:  d.CityStateMailingNameIndicator,
+		PreferredLastLineCityStateKey:  d.PreferredLastLineCityStateKey,
+		PreferredLastLineCityStateName: d.PreferredLastLineCityStateName,
+		CityDeliveryIndicator:          d.CityDeliveryIndicator,
+		CarrierRouteRateSortation:      d.CarrierRouteRateSortation,
+		UniqueZipNameIndicator:         d.UniqueZipNameIndicator,
+		FinanceNumber:                  d.FinanceNumber,
+		StateAbbreviation:              d.StateAbbreviation,
+		CountyNumber:                   d.CountyNumber,
+		CountyName:                     d.CountyName,
+	}
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/corbaltcode/usps/lookup/lookuppb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T, s *Server) lookuppb.LookupServiceClient {
+	lis := bufconn.Listen(1 << 20)
+	g := grpc.NewServer(s.GRPCOptions()...)
+	s.RegisterGRPC(g)
+	go g.Serve(lis)
+	t.Cleanup(g.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return lookuppb.NewLookupServiceClient(conn)
+}
+
+func TestGRPCLookups(t *testing.T) {
+	c := newTestClient(t, newTestServer(t))
+	ctx := context.Background()
+
+	var header metadata.MD
+	zip, err := c.GetZip(ctx, &lookuppb.GetZipRequest{Zip: "22201"}, grpc.Header(&header))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := zip.Zip.PreferredLastLineCityStateName; got != "ARLINGTON" {
+		t.Errorf("preferred city: got %q", got)
+	}
+	if len(zip.Cities) != 2 || len(zip.Counties) != 1 || zip.Counties[0].Fips != "51013" {
+		t.Errorf("cities %v, counties %v", zip.Cities, zip.Counties)
+	}
+	if len(zip.Crosswalk) != 1 || !zip.Crosswalk[0].Primary || zip.Crosswalk[0].AddOnCount != 99 {
+		t.Errorf("crosswalk: %v", zip.Crosswalk)
+	}
+	if got := header.Get(releaseHeader); len(got) != 1 || got[0] != "2026-10" {
+		t.Errorf("release header: %v", got)
+	}
+
+	d, err := c.GetZip4(ctx, &lookuppb.GetZip4Request{Code: "22201-0050"})
+	if err != nil || d.UpdateKey != "0000000001" {
+		t.Errorf("GetZip4: %v, %v", d, err)
+	}
+
+	zips, err := c.GetZipsForCounty(ctx, &lookuppb.GetZipsForCountyRequest{Fips: "51013"})
+	if err != nil || len(zips.Zips) != 1 {
+		t.Errorf("GetZipsForCounty: %v, %v", zips, err)
+	}
+
+	// States are upper-cased like in the REST API.
+	zips, err = c.GetZipsForCity(ctx, &lookuppb.GetZipsForCityRequest{State: " va", Name: "Arlington"})
+	if err != nil || len(zips.Zips) != 1 || zips.Zips[0] != "22201" {
+		t.Errorf("GetZipsForCity: %v, %v", zips, err)
+	}
+
+	cities, err := c.CompleteCity(ctx, &lookuppb.CompleteCityRequest{State: "va", Prefix: "arl", Limit: 1})
+	if err != nil || len(cities.Cities) != 1 {
+		t.Errorf("CompleteCity: %v, %v", cities, err)
+	}
+
+	errs := []struct {
+		err  error
+		code codes.Code
+	}{
+		{func() error { _, err := c.GetZip(ctx, &lookuppb.GetZipRequest{Zip: "99999"}); return err }(), codes.NotFound},
+		{func() error { _, err := c.GetZip(ctx, &lookuppb.GetZipRequest{Zip: "abc"}); return err }(), codes.InvalidArgument},
+		{func() error { _, err := c.GetZip4(ctx, &lookuppb.GetZip4Request{Code: "22201-0500"}); return err }(), codes.NotFound},
+		{func() error {
+			_, err := c.GetZipsForCounty(ctx, &lookuppb.GetZipsForCountyRequest{Fips: "5101"})
+			return err
+		}(), codes.InvalidArgument},
+	}
+	for i, e := range errs {
+		if got := status.Code(e.err); got != e.code {
+			t.Errorf("%d: got %v, want %v (%v)", i, got, e.code, e.err)
+		}
+	}
+}
+
+func TestGRPCStreamZips(t *testing.T) {
+	c := newTestClient(t, newTestServer(t))
+
+	stream, err := c.StreamZips(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, zip := range []string{"22201", "99999", "abc"} {
+		if err := stream.Send(&lookuppb.GetZipRequest{Zip: zip}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stream.CloseSend()
+
+	var results []*lookuppb.ZipResult
+	for {
+		r, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, r)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("got %d results", len(results))
+	}
+	if !results[0].Found || results[0].Response.Zip.ZipCode != "22201" {
+		t.Errorf("22201: %v", results[0])
+	}
+	if results[1].Found || results[1].Error != "" {
+		t.Errorf("99999: %v", results[1])
+	}
+	if results[2].Found || results[2].Error == "" {
+		t.Errorf("abc: %v", results[2])
+	}
+}
+
+func TestGRPCNotReady(t *testing.T) {
+	c := newTestClient(t, New())
+
+	_, err := c.GetZip(context.Background(), &lookuppb.GetZipRequest{Zip: "22201"})
+	if got := status.Code(err); got != codes.Unavailable {
+		t.Errorf("got %v, want %v", got, codes.Unavailable)
+	}
+}
@@ -0,0 +1,326 @@
+// Package server serves lookup.Store lookups over a JSON REST API.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/corbaltcode/usps/citystate"
+	"github.com/corbaltcode/usps/lookup"
+	"github.com/corbaltcode/usps/zip4"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// maxValidateRequests limits the combinations in one POST to /validate.
+const maxValidateRequests = 10000
+
+// maxValidateBodyBytes limits the size of a POST to /validate, allowing
+// maxValidateRequests combinations of typical length.
+const maxValidateBodyBytes = 4 << 20
+
+var fipsPattern = regexp.MustCompile(`^[0-9]{5}$`)
+
+// normalizeState upper-cases a state abbreviation from a request, as stored.
+func normalizeState(state string) string {
+	return strings.ToUpper(strings.TrimSpace(state))
+}
+
+// Server is an http.Handler serving these endpoints:
+//
+//	GET  /zip/{zip}                  ZIP info, cities and counties
+//	GET  /zip4/{zip}-{plus4}         the ZIP+4 record for a nine-digit code
+//	GET  /city?state=&name=          ZIPs for a city
+//	GET  /city?state=&prefix=&limit= city names starting with a prefix
+//	GET  /county/{fips}/zips         ZIPs in a county
+//	GET  /validate?zip=&city=&state= whether a city and state match a ZIP
+//	POST /validate                   the same for a JSON array of combinations
+//	GET  /healthz                    liveness
+//	GET  /readyz                     readiness: data is loaded and readable
+//	GET  /metrics                    Prometheus metrics
+//
+// Lookup responses carry an ETag naming the data release, if it's known.
+type Server struct {
+	mu     sync.RWMutex
+	served *servedStore
+
+	mux     *http.ServeMux
+	metrics *metrics
+}
+
+// New returns a server with no data; it isn't ready until SetStore is
+// called.
+func New() *Server {
+	s := &Server{mux: http.NewServeMux(), metrics: newMetrics()}
+
+	s.handle("/zip/", "zip", s.lookup(s.serveZip))
+	s.handle("/zip4/", "zip4", s.lookup(s.serveZip4))
+	s.handle("/city", "city", s.lookup(s.serveCity))
+	s.handle("/county/", "county", s.lookup(s.serveCounty))
+	s.handle("/validate", "validate", s.serveValidate)
+	s.handle("/healthz", "healthz", s.serveHealth)
+	s.handle("/readyz", "readyz", s.serveReady)
+	s.mux.Handle("/metrics", promhttp.HandlerFor(s.metrics.registry, promhttp.HandlerOpts{}))
+
+	return s
+}
+
+// servedStore is a store being served, with the requests using it counted
+// so it can be closed once they finish after it's replaced.
+type servedStore struct {
+	store   lookup.Store
+	release string
+	inUse   sync.WaitGroup
+}
+
+// SetStore starts serving lookups from a store, replacing the previous one.
+// If the previous store is an io.Closer, it's closed once the requests
+// using it finish.
+func (s *Server) SetStore(store lookup.Store) error {
+	release, err := store.Release()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	old := s.served
+	s.served = &servedStore{store: store, release: release}
+	s.mu.Unlock()
+
+	s.metrics.setRelease(release)
+
+	if old == nil {
+		return nil
+	}
+	if closer, ok := old.store.(io.Closer); ok {
+		go func() {
+			old.inUse.Wait()
+			if err := closer.Close(); err != nil {
+				log.Printf("Failed to close replaced store: %v\n", err)
+			}
+		}()
+	}
+	return nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// acquire returns the current store, or nil if there isn't one, and its
+// release. The caller must call done when it's finished with the store.
+func (s *Server) acquire() (store lookup.Store, release string, done func()) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.served == nil {
+		return nil, "", func() {}
+	}
+	// Counted under the lock, so SetStore can't wait on a store that's
+	// about to be used.
+	s.served.inUse.Add(1)
+	return s.served.store, s.served.release, s.served.inUse.Done
+}
+
+func (s *Server) handle(pattern, route string, h http.HandlerFunc) {
+	s.mux.Handle(pattern, s.metrics.instrument(route, h))
+}
+
+// lookup adapts a GET handler that returns a response or an error, adding
+// ETags and turning errors into statuses.
+func (s *Server) lookup(h func(store lookup.Store, r *http.Request) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+
+		store, release, done := s.acquire()
+		defer done()
+		if store == nil {
+			writeError(w, http.StatusServiceUnavailable, errors.New("data not loaded"))
+			return
+		}
+
+		if release != "" {
+			etag := strconv.Quote(release)
+			w.Header().Set("ETag", etag)
+			if matchesETag(r.Header.Get("If-None-Match"), etag) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+
+		resp, err := h(store, r)
+		if err != nil {
+			writeLookupError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+func matchesETag(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == etag || tag == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// errBadRequest marks errors caused by the request.
+type errBadRequest struct {
+	msg string
+}
+
+func (e errBadRequest) Error() string {
+	return e.msg
+}
+
+func writeLookupError(w http.ResponseWriter, err error) {
+	var bad errBadRequest
+	var invalid *lookup.InvalidError
+	switch {
+	case errors.Is(err, lookup.ErrNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.As(err, &bad), errors.As(err, &invalid):
+		writeError(w, http.StatusBadRequest, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+type ZipResponse struct {
+	Zip      lookup.ZipInfo              `json:"zip"`
+	Cities   []citystate.CityStateDetail `json:"cities"`
+	Counties []lookup.County             `json:"counties"`
+}
+
+func (s *Server) serveZip(store lookup.Store, r *http.Request) (any, error) {
+	zip := strings.TrimPrefix(r.URL.Path, "/zip/")
+
+	info, err := store.Zip(zip)
+	if err != nil {
+		return nil, err
+	}
+	cities, err := store.Cities(zip)
+	if err != nil && !errors.Is(err, lookup.ErrNotFound) {
+		return nil, err
+	}
+	counties, err := store.Counties(zip)
+	if err != nil {
+		return nil, err
+	}
+
+	return ZipResponse{Zip: info, Cities: cities, Counties: counties}, nil
+}
+
+type Zip4Response struct {
+	Zip4 zip4.Zip4Detail `json:"zip4"`
+}
+
+func (s *Server) serveZip4(store lookup.Store, r *http.Request) (any, error) {
+	d, err := store.Zip4(strings.TrimPrefix(r.URL.Path, "/zip4/"))
+	if err != nil {
+		return nil, err
+	}
+	return Zip4Response{Zip4: d}, nil
+}
+
+type CityResponse struct {
+	StateAbbreviation string        `json:"state_abbreviation,omitempty"`
+	Name              string        `json:"name,omitempty"`
+	Zips              []string      `json:"zips,omitempty"`
+	Cities            []lookup.City `json:"cities,omitempty"`
+}
+
+func (s *Server) serveCity(store lookup.Store, r *http.Request) (any, error) {
+	q := r.URL.Query()
+	state := normalizeState(q.Get("state"))
+
+	if q.Has("prefix") {
+		limit := 0
+		if q.Has("limit") {
+			var err error
+			limit, err = strconv.Atoi(q.Get("limit"))
+			if err != nil || limit < 0 {
+				return nil, &lookup.InvalidError{What: "limit", Value: q.Get("limit")}
+			}
+		}
+		cities, err := store.CompleteCity(state, q.Get("prefix"), limit)
+		if err != nil {
+			return nil, err
+		}
+		return CityResponse{StateAbbreviation: state, Cities: cities}, nil
+	}
+
+	if state == "" || q.Get("name") == "" {
+		return nil, errBadRequest{"state and name or prefix are required"}
+	}
+	name := citystate.NormalizeCityName(q.Get("name"))
+	zips, err := store.ZipsForCity(state, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(zips) == 0 {
+		return nil, lookup.ErrNotFound
+	}
+	return CityResponse{StateAbbreviation: state, Name: name, Zips: zips}, nil
+}
+
+type CountyResponse struct {
+	Fips string   `json:"fips"`
+	Zips []string `json:"zips"`
+}
+
+func (s *Server) serveCounty(store lookup.Store, r *http.Request) (any, error) {
+	fips, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/county/"), "/zips")
+	if !ok || !fipsPattern.MatchString(fips) {
+		return nil, lookup.ErrNotFound
+	}
+
+	zips, err := store.ZipsForCounty(fips)
+	if err != nil {
+		return nil, err
+	}
+	if len(zips) == 0 {
+		return nil, lookup.ErrNotFound
+	}
+	return CountyResponse{Fips: fips, Zips: zips}, nil
+}
+
+func (s *Server) serveHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) serveReady(w http.ResponseWriter, r *http.Request) {
+	store, _, done := s.acquire()
+	defer done()
+	if store == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("data not loaded"))
+		return
+	}
+	release, err := store.Release()
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready", "release": release})
+}
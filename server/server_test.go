@@ -0,0 +1,216 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/corbaltcode/usps/citystate"
+	"github.com/corbaltcode/usps/lookup"
+	"github.com/corbaltcode/usps/zip4"
+)
+
+// releaseStore gives a store a release.
+type releaseStore struct {
+	lookup.Store
+	release string
+}
+
+func (s releaseStore) Release() (string, error) {
+	return s.release, nil
+}
+
+func newTestServer(t *testing.T) *Server {
+	store, err := lookup.NewMemoryStore(func(yield func(zip4.Zip4Detail)) error {
+		yield(zip4.Zip4Detail{ZipCode: "22201", UpdateKey: "0000000001", RecordTypeCode: "S", StateAbbreviation: "VA", CountyNumber: "013", Plus4LowNumber: "0001", Plus4HighNumber: "0099"})
+		return nil
+	}, func(yield func(citystate.CityStateDetail)) error {
+		for _, c := range []struct{ key, name, mailing string }{{"X00001", "ARLINGTON", "Y"}, {"X00002", "ARL", "N"}} {
+			yield(citystate.CityStateDetail{
+				ZipCode:                        "22201",
+				CityStateKey:                   c.key,
+				CityStateName:                  c.name,
+				CityStateMailingNameIndicator:  c.mailing,
+				PreferredLastLineCityStateKey:  "X00001",
+				PreferredLastLineCityStateName: "ARLINGTON",
+				StateAbbreviation:              "VA",
+				CountyNumber:                   "013",
+				CountyName:                     "ARLINGTON",
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := New()
+	if err := s.SetStore(releaseStore{store, "2026-10"}); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func get(s *Server, target string, header http.Header) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+	return w
+}
+
+func TestLookups(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		target string
+		status int
+		body   string
+	}{
+		{"/zip/22201", http.StatusOK, `"preferred_last_line_city_state_name":"ARLINGTON"`},
+		{"/zip/99999", http.StatusNotFound, `"error"`},
+		{"/zip/abc", http.StatusBadRequest, `invalid ZIP`},
+		{"/zip4/22201-0050", http.StatusOK, `"update_key":"0000000001"`},
+		{"/zip4/22201-0500", http.StatusNotFound, `"error"`},
+		{"/city?state=va&name=Arlington", http.StatusOK, `"zips":["22201"]`},
+		{"/city?state=VA&prefix=ar&limit=5", http.StatusOK, `"cities":[{"name":"ARLINGTON","state_abbreviation":"VA"}]`},
+		{"/city?state=VA", http.StatusBadRequest, `"error"`},
+		{"/county/51013/zips", http.StatusOK, `"zips":["22201"]`},
+		{"/county/51999/zips", http.StatusNotFound, `"error"`},
+		{"/validate?zip=22201&city=arlington&state=VA", http.StatusOK, `"status":"preferred"`},
+		{"/validate?zip=22201&city=arl&state=VA", http.StatusOK, `"suggestions":[{"city":"ARLINGTON","state":"VA","zip":"22201"}]`},
+		{"/validate?zip=22201&city=arlington&state=MD", http.StatusOK, `"status":"wrong_state"`},
+		{"/validate?zip=99999&city=arlington&state=VA", http.StatusOK, `"status":"unknown_zip"`},
+		{"/validate?zip=abc&city=arlington&state=VA", http.StatusOK, `"zip":"22201"}]`},
+		{"/healthz", http.StatusOK, `"ok"`},
+		{"/readyz", http.StatusOK, `"release":"2026-10"`},
+	}
+	for _, test := range tests {
+		w := get(s, test.target, nil)
+		if w.Code != test.status || !strings.Contains(w.Body.String(), test.body) {
+			t.Errorf("%v: got %v %s, want %v containing %s", test.target, w.Code, w.Body, test.status, test.body)
+		}
+	}
+
+	w := get(s, "/metrics", nil)
+	if !strings.Contains(w.Body.String(), `usps_http_requests_total{code="200",route="zip"} 1`) || !strings.Contains(w.Body.String(), `usps_data_release_info{release="2026-10"} 1`) {
+		t.Errorf("metrics missing counts or release:\n%s", w.Body)
+	}
+}
+
+func TestETag(t *testing.T) {
+	s := newTestServer(t)
+
+	w := get(s, "/zip/22201", nil)
+	etag := w.Header().Get("ETag")
+	if etag != `"2026-10"` {
+		t.Fatalf("got ETag %q, want \"2026-10\"", etag)
+	}
+
+	w = get(s, "/zip/22201", http.Header{"If-None-Match": {etag}})
+	if w.Code != http.StatusNotModified {
+		t.Errorf("got %v, want 304", w.Code)
+	}
+
+	w = get(s, "/zip/22201", http.Header{"If-None-Match": {`"2026-09"`}})
+	if w.Code != http.StatusOK {
+		t.Errorf("stale ETag: got %v, want 200", w.Code)
+	}
+}
+
+func TestValidatePost(t *testing.T) {
+	s := newTestServer(t)
+
+	body := `[{"zip":"22201","city":"Arlington","state":"VA"},{"zip":"22201","city":"Clarendon","state":"VA"}]`
+	r := httptest.NewRequest(http.MethodPost, "/validate", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got %v %s", w.Code, w.Body)
+	}
+
+	var resps []ValidateResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resps); err != nil {
+		t.Fatal(err)
+	}
+	var statuses []citystate.Status
+	for _, resp := range resps {
+		statuses = append(statuses, resp.Status)
+	}
+	if want := []citystate.Status{citystate.StatusPreferred, citystate.StatusNotAcceptable}; !reflect.DeepEqual(statuses, want) {
+		t.Errorf("got %v, want %v", statuses, want)
+	}
+}
+
+func TestValidatePostTooLarge(t *testing.T) {
+	s := newTestServer(t)
+
+	req := `{"zip":"22201","city":"` + strings.Repeat("A", 1000) + `","state":"VA"}`
+	body := "[" + strings.Repeat(req+",", maxValidateBodyBytes/len(req)) + req + "]"
+	r := httptest.NewRequest(http.MethodPost, "/validate", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("got %v %s, want 413", w.Code, w.Body)
+	}
+}
+
+func TestNotReady(t *testing.T) {
+	s := New()
+	for _, target := range []string{"/readyz", "/zip/22201"} {
+		if w := get(s, target, nil); w.Code != http.StatusServiceUnavailable {
+			t.Errorf("%v: got %v, want 503", target, w.Code)
+		}
+	}
+	if w := get(s, "/healthz", nil); w.Code != http.StatusOK {
+		t.Errorf("/healthz: got %v, want 200", w.Code)
+	}
+}
+
+// closingStore records when it's closed.
+type closingStore struct {
+	lookup.Store
+	closed chan struct{}
+}
+
+func (s closingStore) Close() error {
+	close(s.closed)
+	return nil
+}
+
+func TestSetStoreClosesReplacedStoreWhenDrained(t *testing.T) {
+	s := newTestServer(t)
+	old, _, done := s.acquire()
+	done()
+	closed := make(chan struct{})
+	if err := s.SetStore(closingStore{old, closed}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A request holds the store while it's replaced.
+	store, _, done := s.acquire()
+	if err := s.SetStore(old); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Zip("22201"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-closed:
+		t.Fatal("store closed while in use")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	done()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("store not closed after the request finished")
+	}
+}
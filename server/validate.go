@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/corbaltcode/usps/citystate"
+	"github.com/corbaltcode/usps/lookup"
+)
+
+type ValidateRequest struct {
+	Zip   string `json:"zip"`
+	City  string `json:"city"`
+	State string `json:"state"`
+}
+
+type ValidateResponse struct {
+	ValidateRequest
+	citystate.Validation
+}
+
+// serveValidate checks one combination given by query parameters, or a
+// JSON array of them posted.
+func (s *Server) serveValidate(w http.ResponseWriter, r *http.Request) {
+	store, _, done := s.acquire()
+	defer done()
+	if store == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("data not loaded"))
+		return
+	}
+	v := citystate.NewValidator(lookup.CityStateRecords(store))
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		q := r.URL.Query()
+		resp, err := validate(v, ValidateRequest{Zip: q.Get("zip"), City: q.Get("city"), State: q.Get("state")})
+		if err != nil {
+			writeLookupError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+
+	case http.MethodPost:
+		var reqs []ValidateRequest
+		r.Body = http.MaxBytesReader(w, r.Body, maxValidateBodyBytes)
+		if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("request body larger than %v bytes", tooLarge.Limit))
+				return
+			}
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err))
+			return
+		}
+		if len(reqs) > maxValidateRequests {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("at most %v combinations can be validated at once", maxValidateRequests))
+			return
+		}
+
+		resps := make([]ValidateResponse, len(reqs))
+		for i, req := range reqs {
+			resp, err := validate(v, req)
+			if err != nil {
+				writeLookupError(w, fmt.Errorf("combination %v: %w", i, err))
+				return
+			}
+			resps[i] = resp
+		}
+		writeJSON(w, http.StatusOK, resps)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+	}
+}
+
+func validate(v *citystate.Validator, req ValidateRequest) (ValidateResponse, error) {
+	val, err := v.Validate(req.City, req.State, req.Zip)
+	return ValidateResponse{req, val}, err
+}
@@ -0,0 +1,170 @@
+package smarty
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const cacheCreateTableQuery = `CREATE TABLE IF NOT EXISTS smarty_zipcode_cache(
+	Zipcode TEXT PRIMARY KEY,
+	Response TEXT NOT NULL,
+	FetchedAt INTEGER NOT NULL)`
+
+// Cache stores ZIP code lookups in a SQLite database, keyed by ZIP.
+type Cache struct {
+	db *sql.DB
+}
+
+// NewCache returns a cache stored in db, creating its table if needed.
+func NewCache(db *sql.DB) (*Cache, error) {
+	if _, err := db.Exec(cacheCreateTableQuery); err != nil {
+		return nil, fmt.Errorf("failed to create cache table: %w", err)
+	}
+	return &Cache{db: db}, nil
+}
+
+// Get returns the cached response for zip and when it was fetched.
+func (c *Cache) Get(zip string) (Response, time.Time, bool, error) {
+	var raw string
+	var fetchedAt int64
+	err := c.db.QueryRow(`SELECT Response, FetchedAt FROM smarty_zipcode_cache WHERE Zipcode = ?`, zip).Scan(&raw, &fetchedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Response{}, time.Time{}, false, nil
+	}
+	if err != nil {
+		return Response{}, time.Time{}, false, err
+	}
+
+	var r Response
+	if err := json.Unmarshal([]byte(raw), &r); err != nil {
+		return Response{}, time.Time{}, false, fmt.Errorf("failed to unmarshal cached response for %v: %w", zip, err)
+	}
+	r.Raw = json.RawMessage(raw)
+
+	return r, time.Unix(fetchedAt, 0), true, nil
+}
+
+// Put stores the response for zip, replacing any earlier one.
+func (c *Cache) Put(zip string, r Response, fetchedAt time.Time) error {
+	return put(c.db, zip, r, fetchedAt)
+}
+
+// PutBatch stores responses for zips in one transaction.
+func (c *Cache) PutBatch(zips []string, responses []Response, fetchedAt time.Time) error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, zip := range zips {
+		if err := put(tx, zip, responses[i], fetchedAt); err != nil {
+			return fmt.Errorf("failed to cache response for %v: %w", zip, err)
+		}
+	}
+	return tx.Commit()
+}
+
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func put(db execer, zip string, r Response, fetchedAt time.Time) error {
+	raw := r.Raw
+	if raw == nil {
+		var err error
+		raw, err = json.Marshal(r)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := db.Exec(`INSERT OR REPLACE INTO smarty_zipcode_cache(Zipcode, Response, FetchedAt) VALUES(?,?,?)`, zip, string(raw), fetchedAt.Unix())
+	return err
+}
+
+// CachedQuerier answers lookups from a cache, querying only ZIPs that are
+// missing or expired.
+type CachedQuerier struct {
+	querier Querier
+	cache   *Cache
+	// TTL is how long cached responses stay fresh. Zero means forever.
+	TTL time.Duration
+	// ErrorTTL is how long failed lookups, responses with a Status, stay
+	// fresh. Zero means they aren't cached, so they're queried again.
+	ErrorTTL time.Duration
+	// Refresh ignores cached responses, re-querying every ZIP and updating
+	// the cache.
+	Refresh bool
+}
+
+// NewCachedQuerier returns a querier that answers from cache when it can
+// and from querier otherwise.
+func NewCachedQuerier(querier Querier, cache *Cache, ttl time.Duration) *CachedQuerier {
+	return &CachedQuerier{querier: querier, cache: cache, TTL: ttl}
+}
+
+// QueryBatch returns one response per ZIP, in the order given, with
+// InputIndex set to the ZIP's position in zips. Responses fetched from the
+// underlying querier are cached; failed lookups only if ErrorTTL is set.
+func (q *CachedQuerier) QueryBatch(zips []string) ([]Response, error) {
+	responses := make([]Response, len(zips))
+	var missIndexes []int
+	var missZips []string
+
+	now := time.Now()
+	for i, zip := range zips {
+		if !q.Refresh {
+			r, fetchedAt, ok, err := q.cache.Get(zip)
+			if err != nil {
+				return nil, err
+			}
+			if ok && q.fresh(r, now.Sub(fetchedAt)) {
+				r.InputIndex = i
+				responses[i] = r
+				continue
+			}
+		}
+		missIndexes = append(missIndexes, i)
+		missZips = append(missZips, zip)
+	}
+
+	if len(missZips) == 0 {
+		return responses, nil
+	}
+
+	fetched, err := q.querier.QueryBatch(missZips)
+	if err != nil {
+		return nil, err
+	}
+	if len(fetched) != len(missZips) {
+		return nil, fmt.Errorf("mismatched response count: received %d responses for %d queried ZIPs", len(fetched), len(missZips))
+	}
+
+	var putZips []string
+	var putResponses []Response
+	for j, r := range fetched {
+		if r.Status == "" || q.ErrorTTL > 0 {
+			putZips = append(putZips, missZips[j])
+			putResponses = append(putResponses, r)
+		}
+		r.InputIndex = missIndexes[j]
+		responses[missIndexes[j]] = r
+	}
+	if err := q.cache.PutBatch(putZips, putResponses, now); err != nil {
+		return nil, err
+	}
+
+	return responses, nil
+}
+
+// fresh reports whether a cached response of a given age can be used.
+func (q *CachedQuerier) fresh(r Response, age time.Duration) bool {
+	if r.Status != "" {
+		return q.ErrorTTL > 0 && age < q.ErrorTTL
+	}
+	return q.TTL == 0 || age < q.TTL
+}
@@ -0,0 +1,97 @@
+package smarty
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+)
+
+func TestCachedQuerier(t *testing.T) {
+	var requests int32
+	client := NewClient(WithBaseURL(newZipcodeServer(t, &requests).URL))
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	cache, err := NewCache(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := NewCachedQuerier(client, cache, 0)
+	if _, err := q.QueryBatch([]string{"00501"}); err != nil {
+		t.Fatal(err)
+	}
+	responses, err := q.QueryBatch([]string{"00601", "00501"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+	if responses[1].InputIndex != 1 || responses[1].Zipcodes[0].Zipcode != "00501" {
+		t.Errorf("unexpected cached response: %+v", responses[1])
+	}
+
+	q.Refresh = true
+	if _, err := q.QueryBatch([]string{"00501"}); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 {
+		t.Errorf("expected refresh to query again, got %d requests", requests)
+	}
+}
+
+func TestCachedQuerierErrors(t *testing.T) {
+	var requests int32
+	client := NewClient(WithBaseURL(newZipcodeServer(t, &requests).URL))
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	cache, err := NewCache(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Failed lookups aren't cached by default, so they're bought again.
+	q := NewCachedQuerier(client, cache, 0)
+	for i := 0; i < 2; i++ {
+		responses, err := q.QueryBatch([]string{"00000", "00501"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if responses[0].Status != "invalid_zipcode" {
+			t.Fatalf("unexpected response: %+v", responses[0])
+		}
+	}
+	if requests != 2 {
+		t.Errorf("expected failed lookup to be queried again, got %d requests", requests)
+	}
+	if _, _, ok, _ := cache.Get("00000"); ok {
+		t.Error("failed lookup was cached")
+	}
+
+	// With ErrorTTL they're cached until it passes.
+	q.ErrorTTL = time.Hour
+	q.QueryBatch([]string{"00000"})
+	q.QueryBatch([]string{"00000"})
+	if requests != 3 {
+		t.Errorf("expected failed lookup to be cached, got %d requests", requests)
+	}
+	if err := cache.Put("00000", Response{Status: "invalid_zipcode"}, time.Now().Add(-2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	q.QueryBatch([]string{"00000"})
+	if requests != 4 {
+		t.Errorf("expected expired failed lookup to be queried again, got %d requests", requests)
+	}
+}
@@ -0,0 +1,225 @@
+package smarty
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket rate limiter.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter returns a limiter allowing rate events per second on average
+// with bursts of up to burst events.
+func NewLimiter(rate float64, burst int) *Limiter {
+	return &Limiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until an event is allowed. A limiter with a rate of zero or
+// less allows every event.
+func (l *Limiter) Wait() {
+	for {
+		l.mu.Lock()
+		if l.rate <= 0 {
+			l.mu.Unlock()
+			return
+		}
+
+		now := time.Now()
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+		l.last = now
+
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return
+		}
+
+		wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+		time.Sleep(wait)
+	}
+}
+
+// Rate returns the limiter's current rate in events per second.
+func (l *Limiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate
+}
+
+// SetRate changes the limiter's rate in events per second. Lowering the rate
+// discards saved-up burst, so the next event waits for the new rate.
+func (l *Limiter) SetRate(rate float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate <= 0 || (rate > 0 && rate < l.rate) {
+		l.tokens = 0
+		l.last = time.Now()
+	}
+	l.rate = rate
+}
+
+// PoolOptions configures a Pool.
+type PoolOptions struct {
+	// RequestsPerSecond is the highest request rate the pool uses.
+	RequestsPerSecond float64
+	// Concurrency is the number of requests in flight at once.
+	Concurrency int
+	// MinRequestsPerSecond is the floor for slowing down after rate limit
+	// responses. Defaults to a tenth of RequestsPerSecond, or one request per
+	// second if RequestsPerSecond is zero.
+	MinRequestsPerSecond float64
+	// MaxRetries is how many times a batch is retried after a rate limit
+	// response before giving up. Defaults to 5.
+	MaxRetries int
+	// RetryBackoff is how long to wait before the first retry of a batch,
+	// doubling for each retry after it. Defaults to a second.
+	RetryBackoff time.Duration
+}
+
+// Pool spreads lookups of any number of ZIPs over concurrent, rate-limited
+// requests of at most MaxBatchSize ZIPs. After a rate limit response it
+// halves its request rate and retries after a backoff, then speeds back up as
+// requests succeed. A pool with no RequestsPerSecond sends requests as fast
+// as it can until it's rate limited, then starts over from
+// MinRequestsPerSecond.
+type Pool struct {
+	querier Querier
+	limiter *Limiter
+	opts    PoolOptions
+}
+
+// NewPool returns a pool that sends requests to querier, which must accept
+// batches of MaxBatchSize ZIPs and be safe for concurrent use.
+func NewPool(querier Querier, opts PoolOptions) *Pool {
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	if opts.MinRequestsPerSecond == 0 {
+		opts.MinRequestsPerSecond = opts.RequestsPerSecond / 10
+	}
+	if opts.MinRequestsPerSecond <= 0 {
+		opts.MinRequestsPerSecond = 1
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = 5
+	}
+	if opts.RetryBackoff == 0 {
+		opts.RetryBackoff = time.Second
+	}
+
+	return &Pool{
+		querier: querier,
+		limiter: NewLimiter(opts.RequestsPerSecond, opts.Concurrency),
+		opts:    opts,
+	}
+}
+
+// QueryBatch returns one response per ZIP, in the order given, with
+// InputIndex set to the ZIP's position in zips.
+func (p *Pool) QueryBatch(zips []string) ([]Response, error) {
+	var batches [][]string
+	for i := 0; i < len(zips); i += MaxBatchSize {
+		batches = append(batches, zips[i:min(i+MaxBatchSize, len(zips))])
+	}
+
+	results := make([][]Response, len(batches))
+	errs := make([]error, len(batches))
+
+	var wg sync.WaitGroup
+	var failed sync.Once
+	done := make(chan struct{})
+	next := make(chan int)
+
+	for w := 0; w < p.opts.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i], errs[i] = p.query(batches[i])
+				if errs[i] != nil {
+					failed.Do(func() { close(done) })
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for i := range batches {
+		select {
+		case next <- i:
+		case <-done:
+			break dispatch
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	responses := make([]Response, 0, len(zips))
+	for i, batch := range results {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		for _, r := range batch {
+			r.InputIndex = len(responses)
+			responses = append(responses, r)
+		}
+	}
+
+	return responses, nil
+}
+
+func (p *Pool) query(zips []string) ([]Response, error) {
+	for attempt := 0; ; attempt++ {
+		p.limiter.Wait()
+
+		responses, err := p.querier.QueryBatch(zips)
+		if errors.Is(err, ErrRateLimited) && attempt < p.opts.MaxRetries {
+			p.slowDown()
+			time.Sleep(p.opts.RetryBackoff << attempt)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		p.speedUp()
+		return responses, nil
+	}
+}
+
+// slowDown halves the request rate, not going below MinRequestsPerSecond.
+func (p *Pool) slowDown() {
+	rate := p.limiter.Rate()
+	if rate <= 0 {
+		p.limiter.SetRate(p.opts.MinRequestsPerSecond)
+		return
+	}
+	p.limiter.SetRate(max(p.opts.MinRequestsPerSecond, rate/2))
+}
+
+// speedUp raises the request rate by a twentieth of RequestsPerSecond up to
+// it, or by MinRequestsPerSecond without limit if RequestsPerSecond is zero.
+func (p *Pool) speedUp() {
+	rate := p.limiter.Rate()
+	if rate <= 0 {
+		return
+	}
+	if p.opts.RequestsPerSecond <= 0 {
+		p.limiter.SetRate(rate + p.opts.MinRequestsPerSecond)
+		return
+	}
+	p.limiter.SetRate(min(p.opts.RequestsPerSecond, rate+p.opts.RequestsPerSecond/20))
+}
@@ -0,0 +1,156 @@
+package smarty
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPoolOrdersResults(t *testing.T) {
+	var requests int32
+	pool := NewPool(NewClient(WithBaseURL(newZipcodeServer(t, &requests).URL)), PoolOptions{Concurrency: 4})
+
+	var zips []string
+	for i := 0; i < 5*MaxBatchSize+7; i++ {
+		zips = append(zips, fmt.Sprintf("%05d", i+1))
+	}
+
+	responses, err := pool.QueryBatch(zips)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if requests != 6 {
+		t.Errorf("expected 6 requests, got %d", requests)
+	}
+	for i, r := range responses {
+		if r.InputIndex != i || r.Zipcodes[0].Zipcode != zips[i] {
+			t.Fatalf("response %d out of order: %+v", i, r)
+		}
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter(0, 1)
+	start := time.Now()
+	for i := 0; i < 1000; i++ {
+		l.Wait()
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("unlimited limiter waited %v", elapsed)
+	}
+
+	// A burst of 5 goes at once; the next 5 wait 10ms each.
+	l = NewLimiter(100, 5)
+	start = time.Now()
+	for i := 0; i < 10; i++ {
+		l.Wait()
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond || elapsed > 500*time.Millisecond {
+		t.Errorf("10 events at 100/s with burst 5 took %v", elapsed)
+	}
+}
+
+func TestLimiterSetRateDrainsBurst(t *testing.T) {
+	for _, initial := range []float64{0, 1000} {
+		l := NewLimiter(initial, 5)
+		l.SetRate(20)
+
+		start := time.Now()
+		l.Wait()
+		if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+			t.Errorf("from %v/s: first event after lowering the rate to 20/s waited only %v", initial, elapsed)
+		}
+	}
+
+	// Raising the rate keeps the burst.
+	l := NewLimiter(10, 5)
+	l.SetRate(20)
+	start := time.Now()
+	l.Wait()
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("first event after raising the rate waited %v", elapsed)
+	}
+}
+
+// rateLimitedQuerier fails with ErrRateLimited the first failures times it's
+// called and records when each call was made.
+type rateLimitedQuerier struct {
+	failures int
+	calls    []time.Time
+}
+
+func (q *rateLimitedQuerier) QueryBatch(zips []string) ([]Response, error) {
+	q.calls = append(q.calls, time.Now())
+	if len(q.calls) <= q.failures {
+		return nil, ErrRateLimited
+	}
+	responses := make([]Response, len(zips))
+	for i := range zips {
+		responses[i] = Response{InputIndex: i}
+	}
+	return responses, nil
+}
+
+func TestPoolRetriesWithBackoff(t *testing.T) {
+	q := &rateLimitedQuerier{failures: 2}
+	pool := NewPool(q, PoolOptions{RetryBackoff: 20 * time.Millisecond, MinRequestsPerSecond: 1000})
+
+	if _, err := pool.QueryBatch([]string{"00501"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(q.calls) != 3 {
+		t.Fatalf("expected 3 calls, got %d", len(q.calls))
+	}
+	for i, want := range []time.Duration{20 * time.Millisecond, 40 * time.Millisecond} {
+		if gap := q.calls[i+1].Sub(q.calls[i]); gap < want {
+			t.Errorf("retry %d after %v, want at least %v", i+1, gap, want)
+		}
+	}
+	// Without RequestsPerSecond, the pool limits itself once rate limited.
+	if rate := pool.limiter.Rate(); rate <= 0 {
+		t.Errorf("rate after rate limiting = %v, want a limit", rate)
+	}
+}
+
+func TestPoolGivesUp(t *testing.T) {
+	q := &rateLimitedQuerier{failures: 100}
+	pool := NewPool(q, PoolOptions{MaxRetries: 2, RetryBackoff: time.Millisecond, MinRequestsPerSecond: 1000})
+
+	_, err := pool.QueryBatch([]string{"00501"})
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+	if len(q.calls) != 3 {
+		t.Errorf("expected 3 calls, got %d", len(q.calls))
+	}
+}
+
+func TestPoolAdaptsRate(t *testing.T) {
+	q := &rateLimitedQuerier{failures: 2}
+	pool := NewPool(q, PoolOptions{RequestsPerSecond: 400, Concurrency: 1, RetryBackoff: time.Millisecond})
+
+	if _, err := pool.QueryBatch([]string{"00501"}); err != nil {
+		t.Fatal(err)
+	}
+	// Halved twice, then raised by a twentieth of 400.
+	if rate := pool.limiter.Rate(); rate != 120 {
+		t.Errorf("rate = %v, want 120", rate)
+	}
+
+	for i := 0; i < 20; i++ {
+		pool.QueryBatch([]string{"00501"})
+	}
+	if rate := pool.limiter.Rate(); rate != 400 {
+		t.Errorf("rate = %v, want 400 after recovering", rate)
+	}
+
+	q.failures = 100
+	pool.opts.MaxRetries = 5
+	pool.QueryBatch([]string{"00501"})
+	if rate := pool.limiter.Rate(); rate != 40 {
+		t.Errorf("rate = %v, want the floor of 40", rate)
+	}
+}
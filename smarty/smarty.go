@@ -0,0 +1,226 @@
+// Package smarty is a client for the Smarty US ZIP Code and US Street
+// Address APIs.
+package smarty
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// MaxBatchSize is the most ZIPs the API accepts in one request.
+const MaxBatchSize = 100
+
+// ErrRateLimited is returned when the API rejects a request for exceeding
+// the account's rate limit.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// Querier looks up a batch of ZIPs, returning one response per ZIP in the
+// order given. A Client accepts at most MaxBatchSize ZIPs per batch; a Pool
+// accepts any number.
+type Querier interface {
+	QueryBatch(zips []string) ([]Response, error)
+}
+
+type zipcodeRequest struct {
+	Zipcode string `json:"zipcode"`
+}
+
+// Response is the result of looking up one ZIP. Status and Reason are set
+// if the lookup failed, for example because the ZIP is invalid.
+type Response struct {
+	InputIndex int       `json:"input_index"`
+	Status     string    `json:"status,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	Zipcodes   []Zipcode `json:"zipcodes,omitempty"`
+
+	// Raw is the response as returned by the API.
+	Raw json.RawMessage `json:"-"`
+}
+
+// StatusInvalidZipcode is the Status of a lookup of a ZIP that doesn't
+// exist.
+const StatusInvalidZipcode = "invalid_zipcode"
+
+// Zipcode describes a ZIP and the county it lies in. Counties other than
+// the primary one are listed in AlternateCounties.
+type Zipcode struct {
+	Zipcode           string            `json:"zipcode"`
+	ZipcodeType       string            `json:"zipcode_type"`
+	DefaultCity       string            `json:"default_city"`
+	CountyFIPS        string            `json:"county_fips"`
+	CountyName        string            `json:"county_name"`
+	StateAbbreviation string            `json:"state_abbreviation"`
+	State             string            `json:"state"`
+	Latitude          float64           `json:"latitude"`
+	Longitude         float64           `json:"longitude"`
+	Precision         string            `json:"precision"`
+	AlternateCounties []AlternateCounty `json:"alternate_counties"`
+}
+
+// AlternateCounty is another county a ZIP extends into.
+type AlternateCounty struct {
+	CountyFIPS        string `json:"county_fips"`
+	CountyName        string `json:"county_name"`
+	StateAbbreviation string `json:"state_abbreviation"`
+	State             string `json:"state"`
+}
+
+const (
+	DefaultBaseURL       = "https://us-zipcode.api.smarty.com/lookup"
+	DefaultStreetBaseURL = "https://us-street.api.smarty.com/street-address"
+)
+
+// Client is a Smarty API client. It is safe for concurrent use.
+type Client struct {
+	authId        string
+	authToken     string
+	baseURL       string
+	streetBaseURL string
+	httpClient    *http.Client
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithCredentials sets the auth ID and token sent with each request.
+func WithCredentials(authId, authToken string) Option {
+	return func(c *Client) {
+		c.authId = authId
+		c.authToken = authToken
+	}
+}
+
+// WithBaseURL sets the URL of the US ZIP Code API lookup endpoint.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithStreetBaseURL sets the URL of the US Street Address API endpoint.
+func WithStreetBaseURL(streetBaseURL string) Option {
+	return func(c *Client) {
+		c.streetBaseURL = streetBaseURL
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for requests. The default is
+// http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// NewClient returns a client for the production Smarty endpoints unless
+// configured otherwise by opts.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		baseURL:       DefaultBaseURL,
+		streetBaseURL: DefaultStreetBaseURL,
+		httpClient:    http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// QueryBatch looks up at most MaxBatchSize ZIPs with the US ZIP Code API.
+func (client *Client) QueryBatch(zips []string) ([]Response, error) {
+	if len(zips) > MaxBatchSize {
+		return nil, fmt.Errorf("batch of %d ZIPs exceeds maximum of %d", len(zips), MaxBatchSize)
+	}
+
+	var payload []zipcodeRequest
+	for _, zip := range zips {
+		payload = append(payload, zipcodeRequest{Zipcode: zip})
+	}
+
+	body, err := client.post(client.baseURL, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawResponses []json.RawMessage
+	err = json.Unmarshal(body, &rawResponses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	smartyResponses := make([]Response, len(rawResponses))
+	for i, raw := range rawResponses {
+		if err := json.Unmarshal(raw, &smartyResponses[i]); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		smartyResponses[i].Raw = raw
+	}
+
+	return smartyResponses, nil
+}
+
+// post sends payload as JSON to an API endpoint and returns the response
+// body.
+func (client *Client) post(baseURL string, payload any) ([]byte, error) {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON payload: %w", err)
+	}
+
+	v := url.Values{}
+	v.Add("auth-id", client.authId)
+	v.Add("auth-token", client.authToken)
+	apiURL := baseURL + "?" + v.Encode()
+
+	req, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Smarty API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("%w: %s", ErrRateLimited, body)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, body)
+	}
+
+	return body, nil
+}
+
+// ExtractCountyFipsCodes returns the 5-digit FIPS codes of the primary and
+// alternate counties of zipcodes.
+func ExtractCountyFipsCodes(zipcodes []Zipcode) []string {
+	countyFipsCodes := make([]string, 0)
+
+	if zipcodes == nil {
+		return countyFipsCodes
+	}
+
+	for _, zipcode := range zipcodes {
+		countyFipsCodes = append(countyFipsCodes, zipcode.CountyFIPS)
+
+		for _, altCounty := range zipcode.AlternateCounties {
+			countyFipsCodes = append(countyFipsCodes, altCounty.CountyFIPS)
+		}
+	}
+
+	return countyFipsCodes
+}
@@ -0,0 +1,106 @@
+package smarty
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+)
+
+// newZipcodeServer returns a stand-in for the US ZIP Code API that places
+// every ZIP in county 36103, except "00000", which is invalid. It counts
+// the requests it receives in requests.
+func newZipcodeServer(t *testing.T, requests *int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+
+		var zips []zipcodeRequest
+		if err := json.NewDecoder(r.Body).Decode(&zips); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		responses := []Response{}
+		for i, z := range zips {
+			if z.Zipcode == "00000" {
+				responses = append(responses, Response{InputIndex: i, Status: "invalid_zipcode", Reason: "Invalid ZIP Code."})
+				continue
+			}
+			responses = append(responses, Response{
+				InputIndex: i,
+				Zipcodes: []Zipcode{{
+					Zipcode:           z.Zipcode,
+					CountyFIPS:        "36103",
+					AlternateCounties: []AlternateCounty{{CountyFIPS: "36059"}},
+				}},
+			})
+		}
+		json.NewEncoder(w).Encode(responses)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestNewClientDefaults(t *testing.T) {
+	c := NewClient()
+	if c.baseURL != DefaultBaseURL || c.streetBaseURL != DefaultStreetBaseURL || c.httpClient != http.DefaultClient {
+		t.Errorf("unexpected defaults: %+v", c)
+	}
+}
+
+func TestQueryBatch(t *testing.T) {
+	var requests int32
+	srv := newZipcodeServer(t, &requests)
+	client := NewClient(WithBaseURL(srv.URL), WithHTTPClient(srv.Client()))
+
+	responses, err := client.QueryBatch([]string{"00501", "00000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(responses) != 2 {
+		t.Fatalf("expected 2 responses, got %d", len(responses))
+	}
+	fips := ExtractCountyFipsCodes(responses[0].Zipcodes)
+	if len(fips) != 2 || fips[0] != "36103" || fips[1] != "36059" {
+		t.Errorf("unexpected county FIPS codes: %v", fips)
+	}
+	if responses[1].Status != "invalid_zipcode" {
+		t.Errorf("expected invalid_zipcode status, got %q", responses[1].Status)
+	}
+	if len(responses[0].Raw) == 0 {
+		t.Error("expected raw response")
+	}
+}
+
+func TestWithCredentials(t *testing.T) {
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte("[]"))
+	}))
+	defer srv.Close()
+
+	client := NewClient(WithCredentials("id", "token"), WithBaseURL(srv.URL))
+	if _, err := client.QueryBatch([]string{"00501"}); err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("auth-id") != "id" || query.Get("auth-token") != "token" {
+		t.Errorf("unexpected credentials: %v", query)
+	}
+}
+
+func TestQueryBatchRateLimited(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	_, err := NewClient(WithBaseURL(srv.URL)).QueryBatch([]string{"00501"})
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package smarty
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Match strategies for street address lookups.
+const (
+	MatchStrict   = "strict"
+	MatchInvalid  = "invalid"
+	MatchEnhanced = "enhanced"
+)
+
+// StreetLookup is an address to look up with the US Street Address API.
+// Either City and State, ZIPCode, or Lastline must be set.
+type StreetLookup struct {
+	InputID       string `json:"input_id,omitempty"`
+	Street        string `json:"street"`
+	Street2       string `json:"street2,omitempty"`
+	Secondary     string `json:"secondary,omitempty"`
+	City          string `json:"city,omitempty"`
+	State         string `json:"state,omitempty"`
+	ZIPCode       string `json:"zipcode,omitempty"`
+	Lastline      string `json:"lastline,omitempty"`
+	Addressee     string `json:"addressee,omitempty"`
+	Urbanization  string `json:"urbanization,omitempty"`
+	Candidates    int    `json:"candidates,omitempty"`
+	MatchStrategy string `json:"match,omitempty"`
+}
+
+// Candidate is a matched address. An ambiguous input may have several.
+type Candidate struct {
+	InputID              string     `json:"input_id"`
+	InputIndex           int        `json:"input_index"`
+	CandidateIndex       int        `json:"candidate_index"`
+	Addressee            string     `json:"addressee"`
+	DeliveryLine1        string     `json:"delivery_line_1"`
+	DeliveryLine2        string     `json:"delivery_line_2"`
+	LastLine             string     `json:"last_line"`
+	DeliveryPointBarcode string     `json:"delivery_point_barcode"`
+	Components           Components `json:"components"`
+	Metadata             Metadata   `json:"metadata"`
+	Analysis             Analysis   `json:"analysis"`
+}
+
+// Components are the standardized parts of a candidate address.
+type Components struct {
+	Urbanization             string `json:"urbanization"`
+	PrimaryNumber            string `json:"primary_number"`
+	StreetName               string `json:"street_name"`
+	StreetPredirection       string `json:"street_predirection"`
+	StreetPostdirection      string `json:"street_postdirection"`
+	StreetSuffix             string `json:"street_suffix"`
+	SecondaryNumber          string `json:"secondary_number"`
+	SecondaryDesignator      string `json:"secondary_designator"`
+	ExtraSecondaryNumber     string `json:"extra_secondary_number"`
+	ExtraSecondaryDesignator string `json:"extra_secondary_designator"`
+	PMBDesignator            string `json:"pmb_designator"`
+	PMBNumber                string `json:"pmb_number"`
+	CityName                 string `json:"city_name"`
+	DefaultCityName          string `json:"default_city_name"`
+	StateAbbreviation        string `json:"state_abbreviation"`
+	ZIPCode                  string `json:"zipcode"`
+	Plus4Code                string `json:"plus4_code"`
+	DeliveryPoint            string `json:"delivery_point"`
+	DeliveryPointCheckDigit  string `json:"delivery_point_check_digit"`
+}
+
+// Metadata describes a candidate address, including its county.
+type Metadata struct {
+	RecordType               string  `json:"record_type"`
+	ZIPType                  string  `json:"zip_type"`
+	CountyFIPS               string  `json:"county_fips"`
+	CountyName               string  `json:"county_name"`
+	CarrierRoute             string  `json:"carrier_route"`
+	CongressionalDistrict    string  `json:"congressional_district"`
+	BuildingDefaultIndicator string  `json:"building_default_indicator"`
+	RDI                      string  `json:"rdi"`
+	ELOTSequence             string  `json:"elot_sequence"`
+	ELOTSort                 string  `json:"elot_sort"`
+	Latitude                 float64 `json:"latitude"`
+	Longitude                float64 `json:"longitude"`
+	Precision                string  `json:"precision"`
+	TimeZone                 string  `json:"time_zone"`
+	UTCOffset                float64 `json:"utc_offset"`
+	DST                      bool    `json:"dst"`
+}
+
+// Analysis reports how the input matched, including its DPV codes.
+type Analysis struct {
+	DPVMatchCode      string `json:"dpv_match_code"`
+	DPVFootnotes      string `json:"dpv_footnotes"`
+	DPVCMRA           string `json:"dpv_cmra"`
+	DPVVacant         string `json:"dpv_vacant"`
+	DPVNoStat         string `json:"dpv_no_stat"`
+	Active            string `json:"active"`
+	Footnotes         string `json:"footnotes"`
+	LACSLinkCode      string `json:"lacslink_code"`
+	LACSLinkIndicator string `json:"lacslink_indicator"`
+	SuiteLinkMatch    bool   `json:"suitelink_match"`
+	EnhancedMatch     string `json:"enhanced_match"`
+}
+
+// ZIP4 returns the candidate's ZIP+4 code, such as "12345-6789".
+func (c Candidate) ZIP4() string {
+	if c.Components.Plus4Code == "" {
+		return c.Components.ZIPCode
+	}
+	return c.Components.ZIPCode + "-" + c.Components.Plus4Code
+}
+
+// LookupStreet returns the candidates for a single address. There are none
+// if the address couldn't be matched.
+func (client *Client) LookupStreet(lookup StreetLookup) ([]Candidate, error) {
+	candidates, err := client.LookupStreetBatch([]StreetLookup{lookup})
+	if err != nil {
+		return nil, err
+	}
+	return candidates[0], nil
+}
+
+// LookupStreetBatch looks up at most MaxBatchSize addresses, returning the
+// candidates for each in the order given.
+func (client *Client) LookupStreetBatch(lookups []StreetLookup) ([][]Candidate, error) {
+	if len(lookups) == 0 {
+		return nil, nil
+	}
+	if len(lookups) > MaxBatchSize {
+		return nil, fmt.Errorf("batch of %d addresses exceeds maximum of %d", len(lookups), MaxBatchSize)
+	}
+
+	body, err := client.post(client.streetBaseURL, lookups)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []Candidate
+	err = json.Unmarshal(body, &candidates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	results := make([][]Candidate, len(lookups))
+	for _, c := range candidates {
+		if c.InputIndex < 0 || c.InputIndex >= len(lookups) {
+			return nil, fmt.Errorf("candidate has out-of-range input index %d", c.InputIndex)
+		}
+		results[c.InputIndex] = append(results[c.InputIndex], c)
+	}
+
+	return results, nil
+}
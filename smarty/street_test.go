@@ -0,0 +1,123 @@
+package smarty
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newStreetServer returns a stand-in for the US Street Address API that
+// answers each lookup with the candidates in responses keyed by street.
+func newStreetServer(t *testing.T, responses map[string][]Candidate) (*Client, *[]StreetLookup) {
+	var received []StreetLookup
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("auth-id") != "id" || r.URL.Query().Get("auth-token") != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var lookups []StreetLookup
+		if err := json.NewDecoder(r.Body).Decode(&lookups); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = append(received, lookups...)
+
+		candidates := []Candidate{}
+		for i, l := range lookups {
+			for j, c := range responses[l.Street] {
+				c.InputIndex = i
+				c.CandidateIndex = j
+				c.InputID = l.InputID
+				candidates = append(candidates, c)
+			}
+		}
+		json.NewEncoder(w).Encode(candidates)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := NewClient(WithCredentials("id", "token"), WithStreetBaseURL(srv.URL))
+	return client, &received
+}
+
+func TestLookupStreet(t *testing.T) {
+	client, received := newStreetServer(t, map[string][]Candidate{
+		"1600 amphitheatre pkwy": {{
+			DeliveryLine1: "1600 Amphitheatre Pkwy",
+			Components:    Components{ZIPCode: "94043", Plus4Code: "1351"},
+			Metadata:      Metadata{CountyFIPS: "06085"},
+			Analysis:      Analysis{DPVMatchCode: "Y"},
+		}},
+	})
+
+	candidates, err := client.LookupStreet(StreetLookup{
+		Street:        "1600 amphitheatre pkwy",
+		ZIPCode:       "94043",
+		Candidates:    3,
+		MatchStrategy: MatchEnhanced,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(candidates) != 1 {
+		t.Fatalf("expected 1 candidate, got %d", len(candidates))
+	}
+	c := candidates[0]
+	if c.ZIP4() != "94043-1351" {
+		t.Errorf("ZIP4 = %q", c.ZIP4())
+	}
+	if c.Metadata.CountyFIPS != "06085" {
+		t.Errorf("CountyFIPS = %q", c.Metadata.CountyFIPS)
+	}
+	if c.Analysis.DPVMatchCode != "Y" {
+		t.Errorf("DPVMatchCode = %q", c.Analysis.DPVMatchCode)
+	}
+
+	if got := (*received)[0]; got.Candidates != 3 || got.MatchStrategy != MatchEnhanced {
+		t.Errorf("request sent candidates %d and match %q", got.Candidates, got.MatchStrategy)
+	}
+}
+
+func TestLookupStreetBatch(t *testing.T) {
+	client, _ := newStreetServer(t, map[string][]Candidate{
+		"1 main st": {{DeliveryLine1: "1 Main St"}, {DeliveryLine1: "1 Main St Apt 1"}},
+		"2 main st": {{DeliveryLine1: "2 Main St"}},
+	})
+
+	results, err := client.LookupStreetBatch([]StreetLookup{
+		{InputID: "a", Street: "1 main st", ZIPCode: "12345"},
+		{InputID: "b", Street: "no such st", ZIPCode: "12345"},
+		{InputID: "c", Street: "2 main st", ZIPCode: "12345"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if len(results[0]) != 2 || results[0][1].DeliveryLine1 != "1 Main St Apt 1" {
+		t.Errorf("unexpected candidates for first lookup: %+v", results[0])
+	}
+	if len(results[1]) != 0 {
+		t.Errorf("expected no candidates for unmatched lookup, got %+v", results[1])
+	}
+	if len(results[2]) != 1 || results[2][0].InputID != "c" {
+		t.Errorf("unexpected candidates for third lookup: %+v", results[2])
+	}
+}
+
+func TestLookupStreetBatchTooLarge(t *testing.T) {
+	client, received := newStreetServer(t, nil)
+
+	_, err := client.LookupStreetBatch(make([]StreetLookup, MaxBatchSize+1))
+	if err == nil {
+		t.Fatal("expected error for oversized batch")
+	}
+	if len(*received) != 0 {
+		t.Error("oversized batch was sent")
+	}
+}
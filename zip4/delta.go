@@ -0,0 +1,84 @@
+package zip4
+
+import (
+	"bytes"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/corbaltcode/usps/citystate"
+	"github.com/yeka/zip"
+)
+
+// ReadZip4FromZip reads every ZIP+4 text file in a zip, such as a ZIP+4
+// change product, including text files in nested zips. Records in change
+// files carry an ActionCode of Zip4ActionCodeAdd or Zip4ActionCodeDelete.
+func ReadZip4FromZip(zipName string, zipPassword string, yield func(Zip4Detail)) error {
+	return readTextFilesFromZip(zipName, zipPassword, func(r io.Reader) error {
+		return ReadZip4File(r, yield)
+	})
+}
+
+// ReadCityStateFromZip reads every City State text file in a zip, including
+// text files in nested zips.
+//
+// The City State layout has no action code or update key, so a City State
+// change file can only be applied by replacing the records for each ZIP it
+// contains.
+func ReadCityStateFromZip(zipName string, zipPassword string, yield func(citystate.CityStateDetail)) error {
+	return readTextFilesFromZip(zipName, zipPassword, func(r io.Reader) error {
+		return citystate.ReadCityStateFile(r, yield)
+	})
+}
+
+func readTextFilesFromZip(zipName string, zipPassword string, read func(io.Reader) error) error {
+	zr, err := zip.OpenReader(zipName)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	return readTextFiles(&zr.Reader, zipPassword, read)
+}
+
+func readTextFiles(zr *zip.Reader, zipPassword string, read func(io.Reader) error) error {
+	for _, f := range zr.File {
+		ext := strings.ToLower(path.Ext(f.Name))
+		if ext != ".txt" && ext != ".zip" {
+			continue
+		}
+
+		if f.IsEncrypted() {
+			f.SetPassword(zipPassword)
+		}
+		r, err := f.Open()
+		if err != nil {
+			return err
+		}
+
+		if ext == ".txt" {
+			err = read(r)
+			r.Close()
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		bz, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return err
+		}
+
+		inner, err := zip.NewReader(bytes.NewReader(bz), int64(len(bz)))
+		if err != nil {
+			return err
+		}
+		if err := readTextFiles(inner, zipPassword, read); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
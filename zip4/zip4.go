@@ -3,10 +3,14 @@ package zip4
 import (
 	"archive/tar"
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/corbaltcode/usps/citystate"
 	"github.com/yeka/zip"
@@ -14,18 +18,40 @@ import (
 
 const zip4RecordLength = 182
 
+const zip4TarEntry = "epf-zip4natl/zip4/zip4.zip"
+
 const (
 	Zip4CopyrightDetailCodeCopyright = "C"
 	Zip4CopyrightDetailCodeDetail    = "D"
 )
 
+const (
+	Zip4RecordTypeCodeFirm            = "F"
+	Zip4RecordTypeCodeGeneralDelivery = "G"
+	Zip4RecordTypeCodeHighRise        = "H"
+	Zip4RecordTypeCodePOBox           = "P"
+	Zip4RecordTypeCodeRuralRoute      = "R"
+	Zip4RecordTypeCodeStreet          = "S"
+)
+
+// Action codes in change (delta) files. Full releases use "A" for every
+// record.
+const (
+	Zip4ActionCodeAdd    = "A"
+	Zip4ActionCodeDelete = "D"
+)
+
 type Zip4Detail struct {
-	ZipCode           string
-	RecordTypeCode    string
-	StateAbbreviation string
-	CountyNumber      string
-	Plus4LowNumber    Zip4Number
-	Plus4HighNumber   Zip4Number
+	ZipCode string `json:"zip_code"`
+	// UpdateKey uniquely identifies the record across releases; change
+	// files use it to say which record to delete.
+	UpdateKey         string     `json:"update_key"`
+	ActionCode        string     `json:"action_code"`
+	RecordTypeCode    string     `json:"record_type_code"`
+	StateAbbreviation string     `json:"state_abbreviation"`
+	CountyNumber      string     `json:"county_number"`
+	Plus4LowNumber    Zip4Number `json:"plus4_low_number"`
+	Plus4HighNumber   Zip4Number `json:"plus4_high_number"`
 }
 
 type Zip4Number string
@@ -42,7 +68,120 @@ func (n Zip4Number) IsDeliverable() bool {
 	return n.Segment() != "ND"
 }
 
+// Int returns the add-on as an integer. It fails for non-numeric add-ons
+// such as non-deliverable ("ND") segments.
+func (n Zip4Number) Int() (int, error) {
+	return strconv.Atoi(string(n))
+}
+
+// Zip4NumberFromInt formats an add-on as four digits.
+func Zip4NumberFromInt(n int) Zip4Number {
+	return Zip4Number(fmt.Sprintf("%04d", n))
+}
+
+// NonDeliverableZip4Number returns the non-deliverable ("ND") add-on for a
+// sector.
+func NonDeliverableZip4Number(sector string) Zip4Number {
+	return Zip4Number(sector + "ND")
+}
+
+// Header is what the copyright record at the start of a ZIP+4 or City State
+// file says about it.
+type Header struct {
+	// FileVersion is the first day of the month of the file's release, used
+	// as its fulfillment date.
+	FileVersion time.Time
+	// RecordCount is the number of detail records in the file.
+	RecordCount int
+}
+
+// ReadZip4Header returns the header of the ZIP+4 file in a zip4natl tar: the
+// file version of the first ZIP+4 master file and the record counts of all
+// of them. It fails if a master file doesn't start with a valid copyright
+// record, rather than guessing from file times.
+func ReadZip4Header(tarName string, zipPassword string) (Header, error) {
+	var header Header
+	err := readZip4Files(tarName, zipPassword, func(r io.Reader) error {
+		h, err := readHeader(r)
+		if err != nil {
+			return err
+		}
+		if header.FileVersion.IsZero() {
+			header.FileVersion = h.FileVersion
+		}
+		header.RecordCount += h.RecordCount
+		return nil
+	})
+	if err != nil {
+		return Header{}, fmt.Errorf("error reading ZIP+4 header in %v: %v", tarName, err)
+	}
+	return header, nil
+}
+
+// ReadCityStateHeader returns the header of the City State file in a
+// zip4natl tar, which has its own password.
+func ReadCityStateHeader(tarName string, zipPassword string) (Header, error) {
+	var header Header
+	err := readCityStateFile(tarName, zipPassword, func(r io.Reader) error {
+		var err error
+		header, err = readHeader(r)
+		return err
+	})
+	if err != nil {
+		return Header{}, fmt.Errorf("error reading City State header in %v: %v", tarName, err)
+	}
+	return header, nil
+}
+
+// The copyright record's detail code is followed by the file version, a
+// four-digit year (positions 2-5) and two-digit month (positions 6-7), and
+// the ten-digit count of detail records (positions 8-17).
+const headerLength = 17
+
+func readHeader(r io.Reader) (Header, error) {
+	buf := make([]byte, headerLength)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return Header{}, errors.New("no copyright record")
+		}
+		return Header{}, err
+	}
+	if buf[0] != Zip4CopyrightDetailCodeCopyright[0] {
+		return Header{}, errors.New("no copyright record")
+	}
+	return parseHeader(buf)
+}
+
+func parseHeader(buf []byte) (Header, error) {
+	s := string(buf)
+
+	year, err := strconv.Atoi(s[1:5])
+	if err != nil {
+		return Header{}, fmt.Errorf("invalid file version year in copyright record: %q", s[1:5])
+	}
+	month, err := strconv.Atoi(s[5:7])
+	if err != nil || month < 1 || month > 12 {
+		return Header{}, fmt.Errorf("invalid file version month in copyright record: %q", s[5:7])
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(s[7:17]))
+	if err != nil || count < 0 {
+		return Header{}, fmt.Errorf("invalid record count in copyright record: %q", s[7:17])
+	}
+
+	return Header{
+		FileVersion: time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC),
+		RecordCount: count,
+	}, nil
+}
+
 func ReadCityStateFromZip4Tar(tarName string, zipPassword string, yield func(citystate.CityStateDetail)) error {
+	return readCityStateFile(tarName, zipPassword, func(r io.Reader) error {
+		return citystate.ReadCityStateFile(r, yield)
+	})
+}
+
+// readCityStateFile calls fn with the City State file in a zip4natl tar.
+func readCityStateFile(tarName string, zipPassword string, fn func(io.Reader) error) error {
 	bz, err := readTarEntry(tarName, "epf-zip4natl/ctystate/ctystate.zip")
 	if err != nil {
 		return err
@@ -68,11 +207,19 @@ func ReadCityStateFromZip4Tar(tarName string, zipPassword string, yield func(cit
 	}
 	defer r.Close()
 
-	return citystate.ReadCityStateFile(r, yield)
+	return fn(r)
 }
 
 func ReadZip4FromZip4Tar(tarName string, zipPassword string, yield func(Zip4Detail)) error {
-	bz, err := readTarEntry(tarName, "epf-zip4natl/zip4/zip4.zip")
+	return readZip4Files(tarName, zipPassword, func(r io.Reader) error {
+		return ReadZip4File(r, yield)
+	})
+}
+
+// readZip4Files calls fn with each ZIP+4 master file in a zip4natl tar,
+// stopping at the first error.
+func readZip4Files(tarName string, zipPassword string, fn func(io.Reader) error) error {
+	bz, err := readTarEntry(tarName, zip4TarEntry)
 	if err != nil {
 		return err
 	}
@@ -120,7 +267,7 @@ func ReadZip4FromZip4Tar(tarName string, zipPassword string, yield func(Zip4Deta
 		}
 		defer ri.Close()
 
-		if err = ReadZip4File(ri, yield); err != nil {
+		if err = fn(ri); err != nil {
 			return err
 		}
 	}
@@ -159,6 +306,8 @@ func parseZip4Detail(buf []byte) (Zip4Detail, error) {
 
 	var d Zip4Detail
 	d.ZipCode = s[1:6]
+	d.UpdateKey = s[6:16]
+	d.ActionCode = s[16:17]
 	d.RecordTypeCode = s[17:18]
 	d.Plus4LowNumber = Zip4Number(s[140:144])
 	d.Plus4HighNumber = Zip4Number(s[144:148])
@@ -0,0 +1,140 @@
+package zip4
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yeka/zip"
+)
+
+func makeZip(t *testing.T, password string, names []string, files map[string][]byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, name := range names {
+		var f interface{ Write([]byte) (int, error) }
+		var err error
+		if password != "" {
+			f, err = w.Encrypt(name, password, zip.StandardEncryption)
+		} else {
+			f, err = w.Create(name)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write(files[name]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// writeTar writes a zip4natl tar whose ZIP+4 and City State files start
+// with the given records, and returns its path.
+func writeTar(t *testing.T, zip4Header, cityStateHeader string) string {
+	t.Helper()
+
+	detail := "D22201" + strings.Repeat(" ", zip4RecordLength-6)
+	inner := makeZip(t, "", []string{"zip4mst01.txt", "zip4mst01.pdf"}, map[string][]byte{
+		"zip4mst01.txt": []byte(zip4Header + detail),
+		"zip4mst01.pdf": nil,
+	})
+	zip4Zip := makeZip(t, "zip4", []string{"zip4mst01.zip"}, map[string][]byte{"zip4mst01.zip": inner})
+	cityStateZip := makeZip(t, "citystate", []string{"ctystate.txt", "ctystate.pdf"}, map[string][]byte{
+		"ctystate.txt": []byte(cityStateHeader),
+		"ctystate.pdf": nil,
+	})
+
+	name := filepath.Join(t.TempDir(), "zip4natl.tar")
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	tw := tar.NewWriter(f)
+	for _, e := range []struct {
+		name string
+		data []byte
+	}{
+		{zip4TarEntry, zip4Zip},
+		{"epf-zip4natl/ctystate/ctystate.zip", cityStateZip},
+	} {
+		// A ModTime that isn't the file version, to show it's not used.
+		hdr := &tar.Header{Name: e.name, Mode: 0644, Size: int64(len(e.data)), ModTime: time.Date(2001, 2, 3, 0, 0, 0, 0, time.UTC)}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(e.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func copyrightRecord(version, count string) string {
+	s := "C" + version + count + "COPYRIGHT USPS"
+	return s + strings.Repeat(" ", zip4RecordLength-len(s))
+}
+
+func TestReadHeader(t *testing.T) {
+	tarName := writeTar(t, copyrightRecord("202406", "0000000001"), copyrightRecord("202405", "0000000000"))
+
+	got, err := ReadZip4Header(tarName, "zip4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Header{time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC), 1}); got != want {
+		t.Errorf("ReadZip4Header = %+v, want %+v", got, want)
+	}
+
+	got, err = ReadCityStateHeader(tarName, "citystate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Header{time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC), 0}); got != want {
+		t.Errorf("ReadCityStateHeader = %+v, want %+v", got, want)
+	}
+
+	var zips []string
+	err = ReadZip4FromZip4Tar(tarName, "zip4", func(d Zip4Detail) { zips = append(zips, d.ZipCode) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zips) != 1 || zips[0] != "22201" {
+		t.Errorf("copyright record should be skipped; got ZIPs %v", zips)
+	}
+}
+
+func TestReadHeaderErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{"no copyright record", ""},
+		{"invalid year", copyrightRecord("20X406", "0000000001")},
+		{"invalid month", copyrightRecord("202413", "0000000001")},
+		{"invalid count", copyrightRecord("202406", "00000000X1")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tarName := writeTar(t, tt.header, "")
+			if h, err := ReadZip4Header(tarName, "zip4"); err == nil {
+				t.Errorf("expected error, got %+v", h)
+			}
+		})
+	}
+}
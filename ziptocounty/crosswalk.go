@@ -0,0 +1,194 @@
+package ziptocounty
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/corbaltcode/usps/fips"
+	"github.com/corbaltcode/usps/zip4"
+)
+
+// CrosswalkOptions controls which ZIP+4 records contribute to a crosswalk.
+type CrosswalkOptions struct {
+	// DeliverableOnly excludes records whose add-on segment is "ND".
+	DeliverableOnly bool
+	// RecordTypes restricts the crosswalk to the given record type codes. All
+	// record types are included if empty.
+	RecordTypes []string
+}
+
+// CrosswalkEntry is one ZIP-county pair. Ratios follow the HUD USPS
+// crosswalk: each is the share of the ZIP's add-on codes of that kind that
+// fall in the county. Residential counts street, high-rise and rural route
+// records, business counts firm records and other counts PO box and general
+// delivery records.
+type CrosswalkEntry struct {
+	Zipcode          string
+	CountyFips       string
+	AddOnCount       int
+	ResidentialRatio float64
+	BusinessRatio    float64
+	OtherRatio       float64
+	TotalRatio       float64
+	Primary          bool
+}
+
+type addOnCounts struct {
+	residential int
+	business    int
+	other       int
+}
+
+func (c addOnCounts) total() int {
+	return c.residential + c.business + c.other
+}
+
+// CrosswalkBuilder accumulates ZIP+4 records into a weighted crosswalk.
+type CrosswalkBuilder struct {
+	opts        CrosswalkOptions
+	recordTypes map[string]bool
+	counts      map[string]map[string]*addOnCounts
+	err         error
+}
+
+// NewCrosswalkBuilder returns an empty builder.
+func NewCrosswalkBuilder(opts CrosswalkOptions) *CrosswalkBuilder {
+	recordTypes := make(map[string]bool)
+	for _, t := range opts.RecordTypes {
+		recordTypes[t] = true
+	}
+
+	return &CrosswalkBuilder{
+		opts:        opts,
+		recordTypes: recordTypes,
+		counts:      make(map[string]map[string]*addOnCounts),
+	}
+}
+
+// Add weights the record's county by the number of add-on codes it covers.
+// It has the signature expected by zip4.ReadZip4FromZip4Tar.
+func (b *CrosswalkBuilder) Add(detail zip4.Zip4Detail) {
+	if b.err != nil {
+		return
+	}
+	if len(b.recordTypes) > 0 && !b.recordTypes[detail.RecordTypeCode] {
+		return
+	}
+	if b.opts.DeliverableOnly && (!detail.Plus4LowNumber.IsDeliverable() || !detail.Plus4HighNumber.IsDeliverable()) {
+		return
+	}
+
+	county, err := fips.CountyCode(detail.StateAbbreviation, detail.CountyNumber)
+	if err != nil {
+		b.err = fmt.Errorf("ZIP %s: %w", detail.ZipCode, err)
+		return
+	}
+
+	counties, ok := b.counts[detail.ZipCode]
+	if !ok {
+		counties = make(map[string]*addOnCounts)
+		b.counts[detail.ZipCode] = counties
+	}
+	c, ok := counties[county]
+	if !ok {
+		c = &addOnCounts{}
+		counties[county] = c
+	}
+
+	n := addOnCount(detail.Plus4LowNumber, detail.Plus4HighNumber)
+	switch detail.RecordTypeCode {
+	case zip4.Zip4RecordTypeCodeStreet, zip4.Zip4RecordTypeCodeHighRise, zip4.Zip4RecordTypeCodeRuralRoute:
+		c.residential += n
+	case zip4.Zip4RecordTypeCodeFirm:
+		c.business += n
+	default:
+		c.other += n
+	}
+}
+
+// Entries returns the crosswalk sorted by ZIP and county. The primary county
+// of each ZIP is the one with the most add-on codes, with ties going to the
+// lowest FIPS code.
+func (b *CrosswalkBuilder) Entries() ([]CrosswalkEntry, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	zips := make([]string, 0, len(b.counts))
+	for zip := range b.counts {
+		zips = append(zips, zip)
+	}
+	sort.Strings(zips)
+
+	var entries []CrosswalkEntry
+	for _, zip := range zips {
+		counties := b.counts[zip]
+
+		var zipTotals addOnCounts
+		fipsCodes := make([]string, 0, len(counties))
+		for county, c := range counties {
+			zipTotals.residential += c.residential
+			zipTotals.business += c.business
+			zipTotals.other += c.other
+			fipsCodes = append(fipsCodes, county)
+		}
+		sort.Strings(fipsCodes)
+
+		primary := ""
+		for _, county := range fipsCodes {
+			if primary == "" || counties[county].total() > counties[primary].total() {
+				primary = county
+			}
+		}
+
+		for _, county := range fipsCodes {
+			c := counties[county]
+			entries = append(entries, CrosswalkEntry{
+				Zipcode:          zip,
+				CountyFips:       county,
+				AddOnCount:       c.total(),
+				ResidentialRatio: ratio(c.residential, zipTotals.residential),
+				BusinessRatio:    ratio(c.business, zipTotals.business),
+				OtherRatio:       ratio(c.other, zipTotals.other),
+				TotalRatio:       ratio(c.total(), zipTotals.total()),
+				Primary:          county == primary,
+			})
+		}
+	}
+
+	return entries, nil
+}
+
+// BuildUSPSCrosswalk builds a crosswalk from the ZIP+4 data in a USPS
+// zip4natl tar.
+func BuildUSPSCrosswalk(tarName, zipPassword string, opts CrosswalkOptions) ([]CrosswalkEntry, error) {
+	b := NewCrosswalkBuilder(opts)
+
+	err := zip4.ReadZip4FromZip4Tar(tarName, zipPassword, b.Add)
+	if err != nil {
+		return nil, fmt.Errorf("error processing ZIP+4 data: %v", err)
+	}
+
+	return b.Entries()
+}
+
+// addOnCount returns the number of add-on codes in a range. Ranges with
+// non-numeric bounds, such as non-deliverable segments, count as one.
+func addOnCount(low, high zip4.Zip4Number) int {
+	l, err := low.Int()
+	if err != nil {
+		return 1
+	}
+	h, err := high.Int()
+	if err != nil || h < l {
+		return 1
+	}
+	return h - l + 1
+}
+
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
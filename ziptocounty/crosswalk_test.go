@@ -0,0 +1,28 @@
+package ziptocounty
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/corbaltcode/usps/zip4"
+)
+
+func TestCrosswalkBuilder(t *testing.T) {
+	b := NewCrosswalkBuilder(CrosswalkOptions{DeliverableOnly: true})
+	b.Add(zip4.Zip4Detail{ZipCode: "12345", RecordTypeCode: "S", StateAbbreviation: "NY", CountyNumber: "103", Plus4LowNumber: "0001", Plus4HighNumber: "0030"})
+	b.Add(zip4.Zip4Detail{ZipCode: "12345", RecordTypeCode: "F", StateAbbreviation: "NY", CountyNumber: "059", Plus4LowNumber: "0031", Plus4HighNumber: "0040"})
+	b.Add(zip4.Zip4Detail{ZipCode: "12345", RecordTypeCode: "S", StateAbbreviation: "NY", CountyNumber: "059", Plus4LowNumber: "00ND", Plus4HighNumber: "00ND"})
+
+	entries, err := b.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []CrosswalkEntry{
+		{Zipcode: "12345", CountyFips: "36059", AddOnCount: 10, BusinessRatio: 1, TotalRatio: 0.25},
+		{Zipcode: "12345", CountyFips: "36103", AddOnCount: 30, ResidentialRatio: 1, TotalRatio: 0.75, Primary: true},
+	}
+	if !slices.Equal(entries, want) {
+		t.Errorf("entries = %+v, want %+v", entries, want)
+	}
+}
@@ -0,0 +1,94 @@
+package ziptocounty
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/corbaltcode/usps/smarty"
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+)
+
+// ProviderOptions configures the providers opened by OpenProvider.
+type ProviderOptions struct {
+	// ZipPassword unlocks the zip4natl tar of a usps provider.
+	ZipPassword string
+	Smarty      SmartyOptions
+}
+
+// SmartyOptions configures a smarty provider.
+type SmartyOptions struct {
+	AuthID    string
+	AuthToken string
+	// CacheName is a SQLite database caching responses, if set.
+	CacheName string
+	// CacheTTL and ErrorTTL are the CachedQuerier's TTL and ErrorTTL.
+	CacheTTL time.Duration
+	ErrorTTL time.Duration
+	// Refresh re-queries every ZIP, updating the cache.
+	Refresh bool
+	Pool    smarty.PoolOptions
+}
+
+// OpenProvider opens a provider by kind: usps (zip4natl tar), sqlite
+// (seed-db database, opened read-only), hud (crosswalk CSV or XLSX), census
+// (ZCTA relationship file) or smarty, which has no file.
+func OpenProvider(kind, fileName string, opts ProviderOptions) (CountyProvider, error) {
+	if kind != "smarty" {
+		log.Printf("Loading %v provider from %v...\n", kind, fileName)
+	}
+
+	switch kind {
+	case "usps":
+		return NewUSPSProvider(fileName, opts.ZipPassword, CrosswalkOptions{})
+	case "sqlite":
+		return openSQLiteProvider(fileName)
+	case "hud":
+		return NewHUDProvider(fileName)
+	case "census":
+		return NewCensusProvider(fileName)
+	case "smarty":
+		return openSmartyProvider(opts.Smarty)
+	default:
+		return nil, fmt.Errorf("unknown provider: %q", kind)
+	}
+}
+
+// openSQLiteProvider opens a seed-db database read-only, so a mistyped name
+// is an error rather than a new, empty database.
+func openSQLiteProvider(fileName string) (CountyProvider, error) {
+	if _, err := os.Stat(fileName); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite3", "file:"+fileName+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return NewSQLiteProvider(db, CrosswalkOptions{}), nil
+}
+
+func openSmartyProvider(opts SmartyOptions) (CountyProvider, error) {
+	client := smarty.NewClient(smarty.WithCredentials(opts.AuthID, opts.AuthToken))
+	var querier smarty.Querier = smarty.NewPool(client, opts.Pool)
+	if opts.CacheName != "" {
+		db, err := sql.Open("sqlite3", opts.CacheName)
+		if err != nil {
+			return nil, err
+		}
+		cache, err := smarty.NewCache(db)
+		if err != nil {
+			return nil, err
+		}
+		cached := smarty.NewCachedQuerier(querier, cache, opts.CacheTTL)
+		cached.ErrorTTL = opts.ErrorTTL
+		cached.Refresh = opts.Refresh
+		querier = cached
+	}
+	return NewSmartyProvider(querier), nil
+}
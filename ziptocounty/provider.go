@@ -0,0 +1,227 @@
+package ziptocounty
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/corbaltcode/usps/census"
+	"github.com/corbaltcode/usps/hud"
+	"github.com/corbaltcode/usps/smarty"
+)
+
+// County is a county that a provider places a ZIP in. Name and Weight are
+// optional; Weight is the share of the ZIP in the county, or 0 if the
+// provider doesn't weight counties.
+type County struct {
+	Fips   string  `json:"fips"`
+	Name   string  `json:"name,omitempty"`
+	Weight float64 `json:"weight,omitempty"`
+}
+
+// CountyLookup is a provider's counties for one ZIP.
+type CountyLookup struct {
+	Zipcode  string   `json:"zipcode"`
+	Counties []County `json:"counties"`
+	// Found is false if the provider has no record of the ZIP.
+	Found bool `json:"found"`
+	// Error describes a failure to look up this ZIP, such as a Smarty
+	// status, as opposed to a failure of the whole batch.
+	Error string `json:"error,omitempty"`
+	// Status is the provider's code for the failure, such as a Smarty
+	// status, if it has one.
+	Status string `json:"status,omitempty"`
+	// Raw is the provider's response for the ZIP, if it has one.
+	Raw json.RawMessage `json:"raw,omitempty"`
+}
+
+// Fips returns the FIPS codes of the lookup's counties.
+func (l CountyLookup) Fips() []string {
+	codes := make([]string, 0, len(l.Counties))
+	for _, c := range l.Counties {
+		codes = append(codes, c.Fips)
+	}
+	return codes
+}
+
+// CountyProvider is a source of ZIP-to-county assignments.
+type CountyProvider interface {
+	Name() string
+	// LookupCounties returns one lookup per ZIP, in the order given.
+	LookupCounties(zips []string) ([]CountyLookup, error)
+}
+
+// ZipLister is implemented by providers that can enumerate the ZIPs they
+// know about.
+type ZipLister interface {
+	Zipcodes() ([]string, error)
+}
+
+// staticProvider serves lookups from counties loaded up front.
+type staticProvider struct {
+	name     string
+	counties map[string][]County
+}
+
+func (p *staticProvider) Name() string {
+	return p.name
+}
+
+func (p *staticProvider) LookupCounties(zips []string) ([]CountyLookup, error) {
+	lookups := make([]CountyLookup, len(zips))
+	for i, zip := range zips {
+		counties, ok := p.counties[zip]
+		lookups[i] = CountyLookup{Zipcode: zip, Counties: counties, Found: ok}
+	}
+	return lookups, nil
+}
+
+func (p *staticProvider) Zipcodes() ([]string, error) {
+	zips := make([]string, 0, len(p.counties))
+	for zip := range p.counties {
+		zips = append(zips, zip)
+	}
+	sort.Strings(zips)
+	return zips, nil
+}
+
+// add adds a county to a ZIP, summing weights if the county is repeated.
+func (p *staticProvider) add(zip string, county County) {
+	counties := p.counties[zip]
+	for i := range counties {
+		if counties[i].Fips == county.Fips {
+			counties[i].Weight += county.Weight
+			return
+		}
+	}
+	p.counties[zip] = append(counties, county)
+}
+
+func newStaticProvider(name string) *staticProvider {
+	return &staticProvider{name: name, counties: make(map[string][]County)}
+}
+
+// NewUSPSProvider returns a provider backed by the ZIP+4 data in a USPS
+// zip4natl tar. Counties are weighted by their share of the ZIP's add-on
+// codes.
+func NewUSPSProvider(tarName, zipPassword string, opts CrosswalkOptions) (CountyProvider, error) {
+	entries, err := BuildUSPSCrosswalk(tarName, zipPassword, opts)
+	if err != nil {
+		return nil, err
+	}
+	return NewCrosswalkProvider(entries), nil
+}
+
+// NewCrosswalkProvider returns a provider backed by crosswalk entries.
+func NewCrosswalkProvider(entries []CrosswalkEntry) CountyProvider {
+	p := newStaticProvider("usps")
+	for _, e := range entries {
+		p.add(e.Zipcode, County{Fips: e.CountyFips, Weight: e.TotalRatio})
+	}
+	return p
+}
+
+// NewHUDProvider returns a provider backed by a HUD USPS ZIP-COUNTY
+// crosswalk file (CSV or XLSX). Counties are weighted by TOT_RATIO.
+func NewHUDProvider(fileName string) (CountyProvider, error) {
+	p := newStaticProvider("hud")
+
+	err := hud.ReadZipCountyFile(fileName, func(zc hud.ZipCounty) {
+		p.add(zc.ZipCode, County{Fips: zc.CountyFIPS, Weight: zc.TotalRatio})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error processing HUD crosswalk: %v", err)
+	}
+
+	return p, nil
+}
+
+// NewCensusProvider returns a provider backed by a Census ZCTA-to-county
+// relationship file, treating each ZCTA as the ZIP with the same code.
+// Counties are weighted by their share of the ZCTA's land area.
+func NewCensusProvider(fileName string) (CountyProvider, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	p := newStaticProvider("census")
+	landAreas := make(map[string]int64)
+
+	err = census.ReadZCTACountyFile(f, func(zc census.ZCTACounty) {
+		// Weights hold land area until all parts of the ZCTA are read.
+		p.add(zc.ZCTA, County{Fips: zc.CountyFIPS, Name: zc.CountyName, Weight: float64(zc.LandAreaPart)})
+		landAreas[zc.ZCTA] += zc.LandAreaPart
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error processing ZCTA relationship file: %v", err)
+	}
+
+	for zip, counties := range p.counties {
+		for i := range counties {
+			counties[i].Weight = ratio(int(counties[i].Weight), int(landAreas[zip]))
+		}
+	}
+
+	return p, nil
+}
+
+type smartyProvider struct {
+	querier smarty.Querier
+}
+
+// NewSmartyProvider returns a provider that looks up ZIPs with the Smarty
+// US ZIP Code API.
+func NewSmartyProvider(querier smarty.Querier) CountyProvider {
+	return &smartyProvider{querier: querier}
+}
+
+func (p *smartyProvider) Name() string {
+	return "smarty"
+}
+
+// LookupCounties passes zips to the querier in one batch, so there must be
+// no more than the querier accepts.
+func (p *smartyProvider) LookupCounties(zips []string) ([]CountyLookup, error) {
+	responses, err := p.querier.QueryBatch(zips)
+	if err != nil {
+		return nil, err
+	}
+	if len(responses) != len(zips) {
+		return nil, fmt.Errorf("mismatched response count: received %d responses for %d queried ZIPs", len(responses), len(zips))
+	}
+
+	lookups := make([]CountyLookup, len(zips))
+	for i, response := range responses {
+		lookups[i] = smartyLookup(zips[i], response)
+	}
+
+	return lookups, nil
+}
+
+func smartyLookup(zip string, response smarty.Response) CountyLookup {
+	lookup := CountyLookup{Zipcode: zip, Found: response.Status == "", Status: response.Status, Raw: response.Raw}
+	if response.Status != "" {
+		lookup.Error = fmt.Sprintf("Status response: %s, Reason: %s", response.Status, response.Reason)
+	}
+
+	add := func(fips, name string) {
+		for _, c := range lookup.Counties {
+			if c.Fips == fips {
+				return
+			}
+		}
+		lookup.Counties = append(lookup.Counties, County{Fips: fips, Name: name})
+	}
+
+	for _, zipcode := range response.Zipcodes {
+		add(zipcode.CountyFIPS, zipcode.CountyName)
+		for _, altCounty := range zipcode.AlternateCounties {
+			add(altCounty.CountyFIPS, altCounty.CountyName)
+		}
+	}
+
+	return lookup
+}
@@ -0,0 +1,42 @@
+package ziptocounty
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompareProviders(t *testing.T) {
+	base := NewCrosswalkProvider([]CrosswalkEntry{
+		{Zipcode: "12345", CountyFips: "36103", TotalRatio: 1},
+	})
+	other := newStaticProvider("other")
+	other.add("12345", County{Fips: "36059"})
+	other.add("54321", County{Fips: "55025"})
+
+	diffs, err := CompareProviders(base, other, []string{"12345", "54321"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diffs) != 2 {
+		t.Fatalf("expected 2 diffs, got %d", len(diffs))
+	}
+	if diffs[0].Category != CategoryDisjoint {
+		t.Errorf("12345: category = %v", diffs[0].Category)
+	}
+	if diffs[1].Zipcode != "54321" || diffs[1].Category != CategoryMissingFromBase {
+		t.Errorf("54321: %+v", diffs[1])
+	}
+}
+
+func TestOpenProviderMissingSQLiteFile(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "missing.db")
+
+	if _, err := OpenProvider("sqlite", fileName, ProviderOptions{}); err == nil {
+		t.Fatal("expected an error opening a missing database")
+	}
+	if _, err := os.Stat(fileName); !os.IsNotExist(err) {
+		t.Errorf("opening a missing database created it: %v", err)
+	}
+}
@@ -0,0 +1,298 @@
+package ziptocounty
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/corbaltcode/usps/census"
+)
+
+// Categories lists every mismatch category, from best to worst agreement.
+var Categories = []MismatchCategory{
+	CategoryExactMatch,
+	CategorySuperset,
+	CategorySubset,
+	CategoryOverlap,
+	CategoryDisjoint,
+	CategoryMissingFromBase,
+	CategoryMissingFromOther,
+	CategorySourceError,
+}
+
+// Report is the result of comparing two providers over many ZIPs.
+type Report struct {
+	BaseName  string
+	OtherName string
+	Diffs     []ZIPCountyDiff
+	// Centroids, if set, adds ZCTA centroids to the diffs.
+	Centroids map[string]census.ZCTAGazetteer
+}
+
+// Summary counts a report's diffs by category and state.
+type Summary struct {
+	Total      int
+	ByCategory map[MismatchCategory]int
+	// ByState maps state abbreviations to counts by category. ZIPs with no
+	// known county are counted under "".
+	ByState map[string]map[MismatchCategory]int
+	// WorstZIPs are the diffs with the most mismatched counties.
+	WorstZIPs []ZIPCountyDiff
+}
+
+// States returns the states in ByState, sorted.
+func (s Summary) States() []string {
+	states := make([]string, 0, len(s.ByState))
+	for state := range s.ByState {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+	return states
+}
+
+// Summary summarizes the report, keeping the worst ZIPs with at least one
+// mismatch.
+func (r *Report) Summary(worst int) Summary {
+	s := Summary{
+		Total:      len(r.Diffs),
+		ByCategory: make(map[MismatchCategory]int),
+		ByState:    make(map[string]map[MismatchCategory]int),
+	}
+
+	for _, d := range r.Diffs {
+		s.ByCategory[d.Category]++
+
+		state := d.State()
+		if s.ByState[state] == nil {
+			s.ByState[state] = make(map[MismatchCategory]int)
+		}
+		s.ByState[state][d.Category]++
+
+		if d.MismatchCount > 0 {
+			s.WorstZIPs = append(s.WorstZIPs, d)
+		}
+	}
+
+	sort.SliceStable(s.WorstZIPs, func(i, j int) bool {
+		if s.WorstZIPs[i].MismatchCount != s.WorstZIPs[j].MismatchCount {
+			return s.WorstZIPs[i].MismatchCount > s.WorstZIPs[j].MismatchCount
+		}
+		return s.WorstZIPs[i].Zipcode < s.WorstZIPs[j].Zipcode
+	})
+	if len(s.WorstZIPs) > worst {
+		s.WorstZIPs = s.WorstZIPs[:worst]
+	}
+
+	return s
+}
+
+// WriteSummary writes the summary as plain text.
+func (r *Report) WriteSummary(w io.Writer, worst int) error {
+	s := r.Summary(worst)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%v ZIPs compared (%v vs %v)\n\n", s.Total, r.BaseName, r.OtherName)
+
+	fmt.Fprintf(&sb, "By category:\n")
+	for _, c := range Categories {
+		fmt.Fprintf(&sb, "  %-20s %8d\n", c, s.ByCategory[c])
+	}
+
+	fmt.Fprintf(&sb, "\nBy state (ZIPs with any mismatch or error / total):\n")
+	for _, state := range s.States() {
+		counts := s.ByState[state]
+		total := 0
+		for _, n := range counts {
+			total += n
+		}
+		label := state
+		if label == "" {
+			label = "unknown"
+		}
+		fmt.Fprintf(&sb, "  %-8s %8d / %d\n", label, total-counts[CategoryExactMatch], total)
+	}
+
+	fmt.Fprintf(&sb, "\nWorst ZIPs:\n")
+	for _, d := range s.WorstZIPs {
+		fmt.Fprintf(&sb, "  %v  %-20s only in %v: %v; only in %v: %v\n",
+			d.Zipcode, d.Category, r.BaseName, strings.Join(d.OnlyInBase, ","), r.OtherName, strings.Join(d.OnlyInOther, ","))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteCSV writes every diff, one row per ZIP, with a header row.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	headers := []string{"Zipcode", "State", "Category", "Base Fips", "Source Fips", "Only In Base", "Only In Source", "In Both", "Mismatch Count", "Base Error", "Source Error"}
+	if r.Centroids != nil {
+		headers = append(headers, "Latitude", "Longitude")
+	}
+	if err := cw.Write(headers); err != nil {
+		return err
+	}
+
+	for _, d := range r.Diffs {
+		record := []string{
+			d.Zipcode,
+			d.State(),
+			string(d.Category),
+			strings.Join(d.BaseFips, ","),
+			strings.Join(d.OtherFips, ","),
+			strings.Join(d.OnlyInBase, ","),
+			strings.Join(d.OnlyInOther, ","),
+			strings.Join(d.InBoth, ","),
+			strconv.Itoa(d.MismatchCount),
+			d.BaseError,
+			d.OtherError,
+		}
+		if r.Centroids != nil {
+			lat, long := "", ""
+			if g, ok := r.Centroids[d.Zipcode]; ok {
+				lat = strconv.FormatFloat(g.Latitude, 'f', -1, 64)
+				long = strconv.FormatFloat(g.Longitude, 'f', -1, 64)
+			}
+			record = append(record, lat, long)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+type jsonDiff struct {
+	ZIPCountyDiff
+	State     string   `json:"state,omitempty"`
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+}
+
+// WriteJSONLines writes every diff as a JSON object on its own line.
+func (r *Report) WriteJSONLines(w io.Writer) error {
+	enc := json.NewEncoder(w)
+
+	for _, d := range r.Diffs {
+		jd := jsonDiff{ZIPCountyDiff: d, State: d.State()}
+		if g, ok := r.Centroids[d.Zipcode]; ok {
+			jd.Latitude = &g.Latitude
+			jd.Longitude = &g.Longitude
+		}
+		if err := enc.Encode(jd); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteHTML writes a self-contained HTML page with the summary and every
+// diff in sortable tables.
+func (r *Report) WriteHTML(w io.Writer, worst int) error {
+	return htmlTemplate.Execute(w, struct {
+		*Report
+		Summary    Summary
+		Categories []MismatchCategory
+	}{r, r.Summary(worst), Categories})
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"join": strings.Join,
+	"stateLabel": func(state string) string {
+		if state == "" {
+			return "unknown"
+		}
+		return state
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>ZIP-to-county diff: {{.BaseName}} vs {{.OtherName}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.5em; text-align: left; }
+th { background: #eee; cursor: pointer; user-select: none; }
+th.asc::after { content: " \25B2"; }
+th.desc::after { content: " \25BC"; }
+td.num { text-align: right; }
+</style>
+</head>
+<body>
+<h1>ZIP-to-county diff: {{.BaseName}} vs {{.OtherName}}</h1>
+<p>{{.Summary.Total}} ZIPs compared. Click a column heading to sort.</p>
+
+<h2>By category</h2>
+<table class="sortable">
+<thead><tr><th>Category</th><th>ZIPs</th></tr></thead>
+<tbody>
+{{- range .Categories}}
+<tr><td>{{.}}</td><td class="num">{{index $.Summary.ByCategory .}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+
+<h2>By state</h2>
+<table class="sortable">
+<thead><tr><th>State</th>{{range .Categories}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{- range $state := .Summary.States}}
+<tr><td>{{stateLabel $state}}</td>{{range $.Categories}}<td class="num">{{index (index $.Summary.ByState $state) .}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+</table>
+
+<h2>Worst ZIPs</h2>
+<table class="sortable">
+<thead><tr><th>ZIP</th><th>State</th><th>Category</th><th>Mismatches</th><th>Only in {{.BaseName}}</th><th>Only in {{.OtherName}}</th></tr></thead>
+<tbody>
+{{- range .Summary.WorstZIPs}}
+<tr><td>{{.Zipcode}}</td><td>{{.State}}</td><td>{{.Category}}</td><td class="num">{{.MismatchCount}}</td><td>{{join .OnlyInBase ", "}}</td><td>{{join .OnlyInOther ", "}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+
+<h2>All ZIPs</h2>
+<table class="sortable">
+<thead><tr><th>ZIP</th><th>State</th><th>Category</th><th>{{.BaseName}}</th><th>{{.OtherName}}</th><th>Only in {{.BaseName}}</th><th>Only in {{.OtherName}}</th><th>In both</th><th>Mismatches</th><th>Errors</th></tr></thead>
+<tbody>
+{{- range .Diffs}}
+<tr><td>{{.Zipcode}}</td><td>{{.State}}</td><td>{{.Category}}</td><td>{{join .BaseFips ", "}}</td><td>{{join .OtherFips ", "}}</td><td>{{join .OnlyInBase ", "}}</td><td>{{join .OnlyInOther ", "}}</td><td>{{join .InBoth ", "}}</td><td class="num">{{.MismatchCount}}</td><td>{{.BaseError}} {{.OtherError}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+
+<script>
+document.querySelectorAll("table.sortable").forEach(function (table) {
+  table.querySelectorAll("th").forEach(function (th, col) {
+    th.addEventListener("click", function () {
+      var asc = !th.classList.contains("asc");
+      table.querySelectorAll("th").forEach(function (h) { h.classList.remove("asc", "desc"); });
+      th.classList.add(asc ? "asc" : "desc");
+
+      var tbody = table.tBodies[0];
+      var rows = Array.prototype.slice.call(tbody.rows);
+      rows.sort(function (a, b) {
+        var x = a.cells[col].textContent, y = b.cells[col].textContent;
+        var nx = parseFloat(x), ny = parseFloat(y);
+        var c = (!isNaN(nx) && !isNaN(ny)) ? nx - ny : x.localeCompare(y);
+        return asc ? c : -c;
+      });
+      rows.forEach(function (row) { tbody.appendChild(row); });
+    });
+  });
+});
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,206 @@
+package ziptocounty
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/corbaltcode/usps/census"
+)
+
+func testReport() *Report {
+	return &Report{
+		BaseName:  "usps",
+		OtherName: "smarty",
+		Diffs: []ZIPCountyDiff{
+			{Zipcode: "22201", Category: CategoryExactMatch, BaseFips: []string{"51013"}, OtherFips: []string{"51013"}, InBoth: []string{"51013"}},
+			{Zipcode: "22202", Category: CategorySuperset, BaseFips: []string{"51013", "51510"}, OtherFips: []string{"51013"}, OnlyInBase: []string{"51510"}, InBoth: []string{"51013"}, MismatchCount: 1},
+			{Zipcode: "10001", Category: CategoryDisjoint, BaseFips: []string{"36061"}, OtherFips: []string{"36047"}, OnlyInBase: []string{"36061"}, OnlyInOther: []string{"36047"}, MismatchCount: 2},
+			{Zipcode: "10002", Category: CategoryOverlap, BaseFips: []string{"36061", "36047"}, OtherFips: []string{"36061", "36081"}, OnlyInBase: []string{"36047"}, OnlyInOther: []string{"36081"}, InBoth: []string{"36061"}, MismatchCount: 2},
+			{Zipcode: "99999", Category: CategorySourceError, OtherError: "invalid_zipcode", MismatchCount: 0},
+		},
+	}
+}
+
+func TestSummary(t *testing.T) {
+	s := testReport().Summary(2)
+
+	if s.Total != 5 {
+		t.Errorf("Total = %v", s.Total)
+	}
+	wantCategories := map[MismatchCategory]int{
+		CategoryExactMatch:  1,
+		CategorySuperset:    1,
+		CategoryDisjoint:    1,
+		CategoryOverlap:     1,
+		CategorySourceError: 1,
+	}
+	if !reflect.DeepEqual(s.ByCategory, wantCategories) {
+		t.Errorf("ByCategory = %v", s.ByCategory)
+	}
+	wantStates := map[string]map[MismatchCategory]int{
+		"VA": {CategoryExactMatch: 1, CategorySuperset: 1},
+		"NY": {CategoryDisjoint: 1, CategoryOverlap: 1},
+		"":   {CategorySourceError: 1},
+	}
+	if !reflect.DeepEqual(s.ByState, wantStates) {
+		t.Errorf("ByState = %v", s.ByState)
+	}
+	if got := s.States(); !reflect.DeepEqual(got, []string{"", "NY", "VA"}) {
+		t.Errorf("States = %v", got)
+	}
+
+	// Most mismatches first, ties by ZIP; limited to worst.
+	var worst []string
+	for _, d := range s.WorstZIPs {
+		worst = append(worst, d.Zipcode)
+	}
+	if !reflect.DeepEqual(worst, []string{"10001", "10002"}) {
+		t.Errorf("WorstZIPs = %v", worst)
+	}
+	if n := len(testReport().Summary(10).WorstZIPs); n != 3 {
+		t.Errorf("expected 3 ZIPs with mismatches, got %v", n)
+	}
+}
+
+func TestWriteSummary(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReport().WriteSummary(&buf, 1); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"5 ZIPs compared (usps vs smarty)",
+		"  disjoint ",
+		"  NY              2 / 2",
+		"  VA              1 / 2",
+		"  unknown         1 / 1",
+		"  10001  disjoint",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary is missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "  10002  ") {
+		t.Errorf("summary lists more than 1 worst ZIP:\n%s", out)
+	}
+}
+
+func splitFips(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+func TestWriteCSVRoundTrip(t *testing.T) {
+	r := testReport()
+	r.Centroids = map[string]census.ZCTAGazetteer{"22201": {ZCTA: "22201", Latitude: 38.887413, Longitude: -77.093544}}
+
+	var buf bytes.Buffer
+	if err := r.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != len(r.Diffs)+1 || records[0][0] != "Zipcode" || records[0][len(records[0])-1] != "Longitude" {
+		t.Fatalf("unexpected CSV: %v", records)
+	}
+	for i, rec := range records[1:] {
+		n, err := strconv.Atoi(rec[8])
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := ZIPCountyDiff{
+			Zipcode:       rec[0],
+			Category:      MismatchCategory(rec[2]),
+			BaseFips:      splitFips(rec[3]),
+			OtherFips:     splitFips(rec[4]),
+			OnlyInBase:    splitFips(rec[5]),
+			OnlyInOther:   splitFips(rec[6]),
+			InBoth:        splitFips(rec[7]),
+			MismatchCount: n,
+			BaseError:     rec[9],
+			OtherError:    rec[10],
+		}
+		if !reflect.DeepEqual(got, r.Diffs[i]) {
+			t.Errorf("row %d: got %+v, want %+v", i, got, r.Diffs[i])
+		}
+		if rec[1] != r.Diffs[i].State() {
+			t.Errorf("row %d: state %q", i, rec[1])
+		}
+	}
+	if got := records[1][11:]; !reflect.DeepEqual(got, []string{"38.887413", "-77.093544"}) {
+		t.Errorf("centroid = %v", got)
+	}
+	if got := records[2][11:]; !reflect.DeepEqual(got, []string{"", ""}) {
+		t.Errorf("missing centroid = %v", got)
+	}
+}
+
+func TestWriteJSONLinesRoundTrip(t *testing.T) {
+	r := testReport()
+	r.Centroids = map[string]census.ZCTAGazetteer{"22201": {ZCTA: "22201", Latitude: 38.887413, Longitude: -77.093544}}
+
+	var buf bytes.Buffer
+	if err := r.WriteJSONLines(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	sc := bufio.NewScanner(&buf)
+	i := 0
+	for ; sc.Scan(); i++ {
+		var got jsonDiff
+		if err := json.Unmarshal(sc.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got.ZIPCountyDiff, r.Diffs[i]) {
+			t.Errorf("line %d: got %+v, want %+v", i, got.ZIPCountyDiff, r.Diffs[i])
+		}
+		if got.State != r.Diffs[i].State() {
+			t.Errorf("line %d: state %q", i, got.State)
+		}
+		if hasCentroid := got.Latitude != nil; hasCentroid != (i == 0) {
+			t.Errorf("line %d: latitude %v", i, got.Latitude)
+		}
+	}
+	if i != len(r.Diffs) {
+		t.Errorf("got %d lines, want %d", i, len(r.Diffs))
+	}
+}
+
+func TestWriteHTMLEscapes(t *testing.T) {
+	r := &Report{
+		BaseName:  `<script>alert("base")</script>`,
+		OtherName: "smarty",
+		Diffs: []ZIPCountyDiff{
+			{Zipcode: `"><img src=x onerror=alert(1)>`, Category: CategoryDisjoint, BaseFips: []string{"<b>36061</b>"}, OnlyInBase: []string{"<b>36061</b>"}, MismatchCount: 1, OtherError: "<i>oops</i>"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteHTML(&buf, 10); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, raw := range []string{`<script>alert("base")`, `<img src=x`, `<b>36061`, `<i>oops`} {
+		if strings.Contains(out, raw) {
+			t.Errorf("HTML contains unescaped %q", raw)
+		}
+	}
+	for _, escaped := range []string{`&lt;script&gt;alert(&#34;base&#34;)&lt;/script&gt;`, `&lt;img src=x onerror=alert(1)&gt;`, `&lt;b&gt;36061&lt;/b&gt;`, `&lt;i&gt;oops&lt;/i&gt;`} {
+		if !strings.Contains(out, escaped) {
+			t.Errorf("HTML is missing escaped %q", escaped)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package ziptocounty
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/corbaltcode/usps/zip4"
+)
+
+// sqliteBatchSize keeps queries under SQLite's limit on bound parameters.
+const sqliteBatchSize = 500
+
+type sqliteProvider struct {
+	db   *sql.DB
+	opts CrosswalkOptions
+}
+
+// NewSQLiteProvider returns a provider backed by a database created by
+// seed-db. Counties are weighted as in NewUSPSProvider and named from the
+// counties table.
+func NewSQLiteProvider(db *sql.DB, opts CrosswalkOptions) CountyProvider {
+	return &sqliteProvider{db: db, opts: opts}
+}
+
+func (p *sqliteProvider) Name() string {
+	return "sqlite"
+}
+
+func (p *sqliteProvider) LookupCounties(zips []string) ([]CountyLookup, error) {
+	lookups := make([]CountyLookup, 0, len(zips))
+
+	for i := 0; i < len(zips); i += sqliteBatchSize {
+		batch, err := p.lookupBatch(zips[i:min(i+sqliteBatchSize, len(zips))])
+		if err != nil {
+			return nil, err
+		}
+		lookups = append(lookups, batch...)
+	}
+
+	return lookups, nil
+}
+
+func (p *sqliteProvider) lookupBatch(zips []string) ([]CountyLookup, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(zips)), ",")
+	args := make([]any, len(zips))
+	for i, zip := range zips {
+		args[i] = zip
+	}
+
+	b := NewCrosswalkBuilder(p.opts)
+	rows, err := p.db.Query(`SELECT r.ZipCode, r.RecordTypeCode, c.StateAbbreviation, c.CountyNumber, c.CountyFips, COALESCE(c.CountyName, ''), r.Sector, r.Plus4Low, r.Plus4High
+		FROM zip4_ranges r JOIN counties c ON c.CountyFips = r.CountyFips WHERE r.ZipCode IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make(map[string]string)
+	for rows.Next() {
+		var d zip4.Zip4Detail
+		var county, name, sector string
+		var low, high sql.NullInt64
+		if err := rows.Scan(&d.ZipCode, &d.RecordTypeCode, &d.StateAbbreviation, &d.CountyNumber, &county, &name, &sector, &low, &high); err != nil {
+			return nil, err
+		}
+		d.Plus4LowNumber = zip4Number(sector, low)
+		d.Plus4HighNumber = zip4Number(sector, high)
+		b.Add(d)
+		names[county] = name
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	entries, err := b.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	counties := make(map[string][]County)
+	for _, e := range entries {
+		counties[e.Zipcode] = append(counties[e.Zipcode], County{Fips: e.CountyFips, Name: names[e.CountyFips], Weight: e.TotalRatio})
+	}
+
+	lookups := make([]CountyLookup, len(zips))
+	for i, zip := range zips {
+		c, ok := counties[zip]
+		lookups[i] = CountyLookup{Zipcode: zip, Counties: c, Found: ok}
+	}
+
+	return lookups, nil
+}
+
+// zip4Number rebuilds an add-on stored as an integer, or as NULL for a
+// non-deliverable segment of sector.
+func zip4Number(sector string, n sql.NullInt64) zip4.Zip4Number {
+	if !n.Valid {
+		return zip4.NonDeliverableZip4Number(sector)
+	}
+	return zip4.Zip4NumberFromInt(int(n.Int64))
+}
+
+func (p *sqliteProvider) Zipcodes() ([]string, error) {
+	rows, err := p.db.Query(`SELECT DISTINCT ZipCode FROM zip4_ranges ORDER BY ZipCode`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var zips []string
+	for rows.Next() {
+		var zip string
+		if err := rows.Scan(&zip); err != nil {
+			return nil, err
+		}
+		zips = append(zips, zip)
+	}
+
+	return zips, rows.Err()
+}
@@ -0,0 +1,180 @@
+// Package ziptocounty compares ZIP-to-county assignments from different
+// sources, such as USPS ZIP+4 data, Smarty and the HUD crosswalk.
+package ziptocounty
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/corbaltcode/usps/census"
+	"github.com/corbaltcode/usps/fips"
+)
+
+// MismatchCategory classifies how two providers' counties for a ZIP
+// compare. Superset and subset are from the base provider's point of view.
+type MismatchCategory string
+
+const (
+	CategoryExactMatch MismatchCategory = "exact_match"
+	// CategorySuperset means the base has every county the other has, and
+	// more.
+	CategorySuperset MismatchCategory = "superset"
+	// CategorySubset means the other has every county the base has, and
+	// more.
+	CategorySubset MismatchCategory = "subset"
+	// CategoryOverlap means the providers share some counties but each has
+	// counties the other lacks.
+	CategoryOverlap  MismatchCategory = "overlap"
+	CategoryDisjoint MismatchCategory = "disjoint"
+	// CategorySourceError means either provider reported an error for the
+	// ZIP.
+	CategorySourceError MismatchCategory = "source_error"
+	// CategoryMissingFromBase means the base provider, usually USPS, has no
+	// record of the ZIP.
+	CategoryMissingFromBase MismatchCategory = "missing_from_base"
+	// CategoryMissingFromOther means the other provider has no record of
+	// the ZIP.
+	CategoryMissingFromOther MismatchCategory = "missing_from_other"
+)
+
+// ZIPCountyDiff compares a base and other provider's counties for one ZIP.
+// FIPS code slices are sorted.
+type ZIPCountyDiff struct {
+	Zipcode       string           `json:"zipcode"`
+	Category      MismatchCategory `json:"category"`
+	BaseFips      []string         `json:"base_fips"`
+	OtherFips     []string         `json:"other_fips"`
+	OnlyInBase    []string         `json:"only_in_base"`
+	OnlyInOther   []string         `json:"only_in_other"`
+	InBoth        []string         `json:"in_both"`
+	MismatchCount int              `json:"mismatch_count"`
+	BaseError     string           `json:"base_error,omitempty"`
+	OtherError    string           `json:"other_error,omitempty"`
+}
+
+// State returns the abbreviation of the state the ZIP's counties lie in,
+// or "" if neither provider placed it in a known county.
+func (d ZIPCountyDiff) State() string {
+	for _, codes := range [][]string{d.BaseFips, d.OtherFips} {
+		for _, code := range codes {
+			if len(code) != 5 {
+				continue
+			}
+			if abbr, ok := fips.StateAbbreviation(code[:2]); ok {
+				return abbr
+			}
+		}
+	}
+	return ""
+}
+
+// CompareProviders looks up zips in both providers and diffs the results.
+func CompareProviders(base, other CountyProvider, zips []string) ([]ZIPCountyDiff, error) {
+	baseLookups, err := base.LookupCounties(zips)
+	if err != nil {
+		return nil, fmt.Errorf("%v lookup failed: %w", base.Name(), err)
+	}
+	otherLookups, err := other.LookupCounties(zips)
+	if err != nil {
+		return nil, fmt.Errorf("%v lookup failed: %w", other.Name(), err)
+	}
+
+	diffs := make([]ZIPCountyDiff, len(zips))
+	for i := range zips {
+		diffs[i] = GenerateDiff(baseLookups[i], otherLookups[i])
+	}
+
+	return diffs, nil
+}
+
+// GenerateDiff compares the counties two providers report for the same ZIP.
+func GenerateDiff(base, other CountyLookup) ZIPCountyDiff {
+	baseFips := base.Fips()
+	otherFips := other.Fips()
+
+	onlyInBase, onlyInOther, inBoth := compareSets(baseFips, otherFips)
+
+	diff := ZIPCountyDiff{
+		Zipcode:       base.Zipcode,
+		BaseFips:      baseFips,
+		OtherFips:     otherFips,
+		OnlyInBase:    onlyInBase,
+		OnlyInOther:   onlyInOther,
+		InBoth:        inBoth,
+		MismatchCount: len(onlyInBase) + len(onlyInOther),
+		BaseError:     base.Error,
+		OtherError:    other.Error,
+	}
+
+	switch {
+	case base.Error != "" || other.Error != "":
+		diff.Category = CategorySourceError
+	case !base.Found:
+		diff.Category = CategoryMissingFromBase
+	case !other.Found:
+		diff.Category = CategoryMissingFromOther
+	case diff.MismatchCount == 0:
+		diff.Category = CategoryExactMatch
+	case len(inBoth) == 0:
+		diff.Category = CategoryDisjoint
+	case len(onlyInOther) == 0:
+		diff.Category = CategorySuperset
+	case len(onlyInBase) == 0:
+		diff.Category = CategorySubset
+	default:
+		diff.Category = CategoryOverlap
+	}
+
+	return diff
+}
+
+// compareSets sorts a and b and splits their elements into those only in a,
+// only in b and in both.
+func compareSets(a, b []string) (onlyInA, onlyInB, inBoth []string) {
+	sort.Strings(a)
+	sort.Strings(b)
+
+	onlyInA, onlyInB, inBoth = []string{}, []string{}, []string{}
+	i, j := 0, 0
+
+	for i < len(a) && j < len(b) {
+		if a[i] == b[j] {
+			inBoth = append(inBoth, a[i])
+			i++
+			j++
+		} else if a[i] < b[j] {
+			onlyInA = append(onlyInA, a[i])
+			i++
+		} else {
+			onlyInB = append(onlyInB, b[j])
+			j++
+		}
+	}
+
+	onlyInA = append(onlyInA, a[i:]...)
+	onlyInB = append(onlyInB, b[j:]...)
+
+	return onlyInA, onlyInB, inBoth
+}
+
+// CollectZCTACentroids reads a Census ZCTA Gazetteer file into a map from
+// ZIP to the Gazetteer entry of the ZCTA with the same code.
+func CollectZCTACentroids(fileName string) (map[string]census.ZCTAGazetteer, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	centroids := make(map[string]census.ZCTAGazetteer)
+
+	err = census.ReadZCTAGazetteerFile(f, func(g census.ZCTAGazetteer) {
+		centroids[g.ZCTA] = g
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error processing ZCTA Gazetteer file: %v", err)
+	}
+
+	return centroids, nil
+}
@@ -0,0 +1,57 @@
+package ziptocounty
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGenerateDiff(t *testing.T) {
+	lookup := func(found bool, fips ...string) CountyLookup {
+		l := CountyLookup{Zipcode: "12345", Found: found}
+		for _, f := range fips {
+			l.Counties = append(l.Counties, County{Fips: f})
+		}
+		return l
+	}
+
+	tests := []struct {
+		name     string
+		base     CountyLookup
+		other    CountyLookup
+		category MismatchCategory
+		count    int
+	}{
+		{"exact", lookup(true, "36103", "36059"), lookup(true, "36059", "36103"), CategoryExactMatch, 0},
+		{"superset", lookup(true, "36103", "36059"), lookup(true, "36103"), CategorySuperset, 1},
+		{"subset", lookup(true, "36103"), lookup(true, "36059", "36103"), CategorySubset, 1},
+		{"overlap", lookup(true, "36103", "36059"), lookup(true, "36103", "36081"), CategoryOverlap, 2},
+		{"disjoint", lookup(true, "36103"), lookup(true, "06103"), CategoryDisjoint, 2},
+		{"missing from base", lookup(false), lookup(true, "36103"), CategoryMissingFromBase, 1},
+		{"missing from other", lookup(true, "36103"), lookup(false), CategoryMissingFromOther, 1},
+		{"source error", lookup(true, "36103"), CountyLookup{Zipcode: "12345", Error: "invalid_zipcode"}, CategorySourceError, 1},
+	}
+
+	for _, tt := range tests {
+		diff := GenerateDiff(tt.base, tt.other)
+		if diff.Category != tt.category {
+			t.Errorf("%v: category = %v, want %v", tt.name, diff.Category, tt.category)
+		}
+		if diff.MismatchCount != tt.count {
+			t.Errorf("%v: mismatch count = %v, want %v", tt.name, diff.MismatchCount, tt.count)
+		}
+	}
+}
+
+func TestGenerateDiffKeepsSourceError(t *testing.T) {
+	base := CountyLookup{Zipcode: "12345", Found: true, Counties: []County{{Fips: "36103"}}}
+	other := CountyLookup{Zipcode: "12345", Error: "invalid_zipcode"}
+
+	diff := GenerateDiff(base, other)
+
+	if diff.OtherError != "invalid_zipcode" {
+		t.Errorf("OtherError = %q", diff.OtherError)
+	}
+	if !slices.Equal(diff.OnlyInBase, []string{"36103"}) || len(diff.OnlyInOther) != 0 || len(diff.InBoth) != 0 {
+		t.Errorf("unexpected county split: %+v", diff)
+	}
+}